@@ -0,0 +1,194 @@
+package csgo
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// analyzedEncodings lists the encodings estimated by Analyze in order of preference.
+var analyzedEncodings = []Compression{NOCOMP, RLE, DICT, DICTRLE, DICTFOR, FOR, DELTA, BITPACK, XOR, FRONTCODING, STRINGHEAP}
+
+// ColumnStats contains statistics about the values of a column (see Relation.Analyze).
+type ColumnStats struct {
+	// Signature is the signature of the analyzed column.
+	Signature AttrInfo
+	// NumRows is the number of rows of the column.
+	NumRows int
+	// Runs is the number of runs of equal consecutive values.
+	Runs int
+	// Distinct is the number of distinct values.
+	Distinct int
+	// EstimatedBytes contains the estimated memory usage of the column for every applicable
+	// encoding. It is empty for grouped columns.
+	EstimatedBytes map[Compression]int
+	// Best is the encoding with the lowest estimated memory usage.
+	Best Compression
+}
+
+// String returns a readable report of the statistics.
+func (stats ColumnStats) String() string {
+	desc := fmt.Sprintf("%s (%s): %d rows, %d runs, %d distinct values, best encoding: %v",
+		stats.Signature.Name, stats.Signature.Enc, stats.NumRows, stats.Runs, stats.Distinct, stats.Best)
+
+	for _, enc := range analyzedEncodings {
+		if bytes, found := stats.EstimatedBytes[enc]; found {
+			desc += fmt.Sprintf("\n  %v: ~%d bytes", enc, bytes)
+		}
+	}
+	return desc
+}
+
+// analyzeColumn collects the statistics of a single column.
+func analyzeColumn(col *Column) ColumnStats {
+	stats := ColumnStats{Signature: col.Signature, NumRows: col.GetNumRows(), Best: col.Signature.Enc}
+	if col.Signature.Flags&GROUPED != 0 {
+		return stats
+	}
+
+	values, err := boxValues(col.Signature.Type, 0, col.GetRawData())
+	if err != nil {
+		return stats
+	}
+
+	// sizes of all values, of the first value of every run and of all distinct values
+	valueSize, runSize, distinctSize := 0, 0, 0
+	distinct := map[interface{}]struct{}{}
+	// the integer encodings are only estimated for INT columns
+	forSize, packedWidth, deltaWidth := 0, 0, 0
+
+	for row, value := range values {
+		size := valueBytes(value)
+		valueSize += size
+
+		if row == 0 || value != values[row-1] {
+			stats.Runs++
+			runSize += size
+		}
+
+		if _, found := distinct[value]; !found {
+			distinct[value] = struct{}{}
+			distinctSize += size
+		}
+
+		if intValue, isInt := value.(int); isInt {
+			packedWidth = max(packedWidth, bits.Len64(zigzag(intValue)))
+			if row%DeltaBlockSize != 0 {
+				deltaWidth = max(deltaWidth, bits.Len64(zigzag(intValue-values[row-1].(int))))
+			}
+
+			if row%FORBlockSize == 0 {
+				blockEnd := min(row+FORBlockSize, len(values))
+				width := 1
+				for _, blockValue := range values[row:blockEnd] {
+					width = max(width, offsetWidth(zigzag(blockValue.(int)-intValue)))
+				}
+				forSize += (blockEnd-row)*width + 2*wordBytes + sliceHeaderBytes
+			}
+		}
+	}
+	stats.Distinct = len(distinct)
+
+	// every distinct value is stored boxed in the dictionary and as key of the reverse map (both
+	// maps share the string data)
+	reverseKeyBytes := wordBytes
+	if col.Signature.Type == STRING {
+		reverseKeyBytes = stringHeaderBytes
+	}
+	dictSize := stats.Distinct*(wordBytes+interfaceBytes+mapEntryBytes) + distinctSize +
+		stats.Distinct*(reverseKeyBytes+wordBytes+mapEntryBytes)
+	// every code run consists of the count, the boxed code and the run end
+	codeRunSize := stats.Runs * (wordBytes + interfaceBytes + 2*wordBytes)
+	codeWidth := offsetWidth(zigzag(stats.Distinct))
+	numBlocks := (stats.NumRows + FORBlockSize - 1) / FORBlockSize
+
+	stats.EstimatedBytes = map[Compression]int{
+		NOCOMP:  valueSize,
+		RLE:     stats.Runs*(wordBytes+interfaceBytes+wordBytes) + runSize,
+		DICT:    dictSize + stats.NumRows*wordBytes,
+		DICTRLE: dictSize + codeRunSize,
+		DICTFOR: dictSize + stats.NumRows*codeWidth + numBlocks*(2*wordBytes+sliceHeaderBytes),
+	}
+	if col.Signature.Type == INT {
+		stats.EstimatedBytes[FOR] = forSize
+		numCheckpoints := (stats.NumRows + DeltaBlockSize - 1) / DeltaBlockSize
+		stats.EstimatedBytes[DELTA] = (stats.NumRows*deltaWidth+7)/8 + numCheckpoints*wordBytes
+		stats.EstimatedBytes[BITPACK] = (stats.NumRows*packedWidth + 7) / 8
+	}
+	if col.Signature.Type == FLOAT {
+		// the size of the XOR encoding depends on the bit patterns, so the column gets encoded
+		xor := NewXORDataStore()
+		xor.AddRows(FLOAT, col.GetRawData())
+		stats.EstimatedBytes[XOR] = xor.MemoryUsage()
+	}
+	if col.Signature.Type == STRING {
+		// the size of the front coding depends on the shared prefixes, so the column gets encoded
+		frontCoded := NewFrontCodedDataStore()
+		frontCoded.AddRows(STRING, col.GetRawData())
+		stats.EstimatedBytes[FRONTCODING] = frontCoded.MemoryUsage()
+		stats.EstimatedBytes[STRINGHEAP] = valueSize - stats.NumRows*(stringHeaderBytes-wordBytes)
+	}
+
+	stats.Best = NOCOMP
+	for _, enc := range analyzedEncodings {
+		if bytes, found := stats.EstimatedBytes[enc]; found && bytes < stats.EstimatedBytes[stats.Best] {
+			stats.Best = enc
+		}
+	}
+	return stats
+}
+
+// Analyze returns statistics about the values of every column of the relation, including the
+// estimated memory usage for all applicable encodings.
+func (r Relation) Analyze() []ColumnStats {
+	stats := make([]ColumnStats, len(r.Columns))
+
+	parallelFor(len(r.Columns), NumWorkers, func(colIndex int) {
+		stats[colIndex] = analyzeColumn(&r.Columns[colIndex])
+	})
+	return stats
+}
+
+// Reencode rebuilds the data of the column col using the encoding enc and returns the new
+// signature of the column. The column is replaced in place, so all relations sharing the columns
+// of r see the new encoding.
+func (r Relation) Reencode(col AttrInfo, enc Compression) (AttrInfo, error) {
+	for colIndex := range r.Columns {
+		if !r.Columns[colIndex].Signature.matches(col) {
+			continue
+		}
+
+		signature := r.Columns[colIndex].Signature
+		signature.Enc = enc
+		reencoded := NewColumn(signature)
+
+		if _, err := reencoded.AddRows(signature.Type, r.Columns[colIndex].GetRawData()); err != nil {
+			return col, err
+		}
+
+		if ds, isDict := reencoded.Data.(*DictEncodedDataStore); isDict {
+			ds.SortDictionary()
+		}
+
+		r.Columns[colIndex] = reencoded
+		return signature, nil
+	}
+
+	return col, errors.New("column not found")
+}
+
+// Optimize reencodes every column using the encoding with the lowest estimated memory usage (see
+// Analyze) and returns the new signatures of all columns.
+func (r Relation) Optimize() []AttrInfo {
+	signatures := []AttrInfo{}
+
+	for _, stats := range r.Analyze() {
+		signature := stats.Signature
+		if stats.Best != signature.Enc {
+			signature, _ = r.Reencode(signature, stats.Best)
+		}
+		signatures = append(signatures, signature)
+	}
+
+	return signatures
+}
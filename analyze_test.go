@@ -0,0 +1,82 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelationAnalyze(t *testing.T) {
+	sorted, unique, lowCardinality := []string{}, []int{}, []float64{}
+	for i := 0; i < 3000; i++ {
+		sorted = append(sorted, []string{"Australia", "Canada", "Germany"}[i/1000])
+		unique = append(unique, i*7919)
+		lowCardinality = append(lowCardinality, float64(i%3)/2)
+	}
+
+	r := Relation{Name: "rel", Columns: []Column{
+		NewColumnWithData(AttrInfo{"sorted", STRING, NOCOMP, 0}, sorted),
+		NewColumnWithData(AttrInfo{"unique", INT, NOCOMP, 0}, unique),
+		NewColumnWithData(AttrInfo{"lowCardinality", FLOAT, NOCOMP, 0}, lowCardinality),
+	}}
+
+	cases := []struct {
+		runs     int
+		distinct int
+		best     Compression
+	}{
+		{runs: 3, distinct: 3, best: RLE},
+		{runs: 3000, distinct: 3000, best: DELTA},
+		{runs: 3000, distinct: 3, best: DICTFOR},
+	}
+
+	stats := r.Analyze()
+	for colIndex, testCase := range cases {
+		if stats[colIndex].NumRows != 3000 || stats[colIndex].Runs != testCase.runs || stats[colIndex].Distinct != testCase.distinct || stats[colIndex].Best != testCase.best {
+			t.Errorf("column %d: unexpected statistics %v", colIndex, stats[colIndex])
+		}
+	}
+
+	if _, found := stats[2].EstimatedBytes[FOR]; found {
+		t.Error("frame of reference encoding estimated for a FLOAT column")
+	}
+
+	signatures := r.Optimize()
+	for colIndex, testCase := range cases {
+		if signatures[colIndex].Enc != testCase.best || r.Columns[colIndex].Signature != signatures[colIndex] {
+			t.Errorf("column %d: expected encoding %v, got %v", colIndex, testCase.best, signatures[colIndex])
+		}
+	}
+
+	data, _ := r.GetRawData()
+	if !reflect.DeepEqual(data, []interface{}{sorted, unique, lowCardinality}) {
+		t.Error("optimizing changed the data of the relation")
+	}
+}
+
+func TestRelationReencode(t *testing.T) {
+	values := []string{"b", "a", "b", "c"}
+	r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(AttrInfo{"col", STRING, NOCOMP, 0}, values)}}
+
+	signature, err := r.Reencode(AttrInfo{"col", STRING, NOCOMP, 0}, DICTRLE)
+	if err != nil || signature != (AttrInfo{"col", STRING, DICTRLE, 0}) {
+		t.Fatalf("unexpected result %v (%v)", signature, err)
+	}
+
+	ds, isDict := r.Columns[0].Data.(*DictEncodedDataStore)
+	if !isDict || !ds.Sorted || !reflect.DeepEqual(r.Columns[0].GetRawData(), values) {
+		t.Error("column was not reencoded into a sorted dictionary")
+	}
+
+	// the original signature still references the column
+	data, _ := r.Select(AttrInfo{"col", STRING, NOCOMP, 0}, EQ, "b").GetRawData()
+	if !reflect.DeepEqual(data, []interface{}{[]string{"b", "b"}}) {
+		t.Errorf("select using the original signature returned %v", data)
+	}
+	if signature, err := r.Reencode(AttrInfo{"col", STRING, NOCOMP, 0}, RLE); err != nil || signature.Enc != RLE {
+		t.Errorf("reencoding using the original signature failed: %v", err)
+	}
+
+	if _, err := r.Reencode(AttrInfo{"col", INT, NOCOMP, 0}, RLE); err == nil {
+		t.Error("reencoding a column with a wrong signature succeeded")
+	}
+}
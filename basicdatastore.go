@@ -90,3 +90,36 @@ func (ds BasicDataStore) GetRow(rowIndex int) (interface{}, error) {
 func (ds BasicDataStore) GetNumRows() int {
 	return len(ds.Values)
 }
+
+// AddRows adds all values to the DataStore and returns the index of the first added row.
+func (ds *BasicDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("invalid data type")
+	}
+
+	boxed, err := boxValues(ds.DataType, ds.Flags, values)
+	if err != nil {
+		return -1, err
+	}
+
+	firstIndex := len(ds.Values)
+	ds.Values = append(ds.Values, boxed...)
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end).
+func (ds BasicDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, len(ds.Values)); err != nil {
+		return nil, err
+	}
+	return unboxValues(ds.DataType, ds.Flags, ds.Values[start:end])
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds BasicDataStore) MemoryUsage() int {
+	size := 2*wordBytes + sliceHeaderBytes + cap(ds.Values)*interfaceBytes
+	for _, value := range ds.Values {
+		size += valueBytes(value)
+	}
+	return size
+}
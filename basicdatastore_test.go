@@ -33,3 +33,10 @@ func TestBasicDataStoreGetDataType(t *testing.T) {
 		testDataStoreGetDataType(&ds, ds.DataType, t)
 	}
 }
+
+func TestBasicDataStoreAddRowsGetRange(t *testing.T) {
+	testDataStoreAddRowsGetRange(NewBasicDataStore(INT, 0), []int{1, 2, 2, 3}, t)
+	testDataStoreAddRowsGetRange(NewBasicDataStore(FLOAT, 0), []float64{0.5, 1.5}, t)
+	testDataStoreAddRowsGetRange(NewBasicDataStore(STRING, 0), []string{"a", "b", "b"}, t)
+	testDataStoreAddRowsGetRange(NewBasicDataStore(INT, GROUPED), [][]int{{1, 2}, {3}}, t)
+}
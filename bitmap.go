@@ -0,0 +1,321 @@
+package csgo
+
+import "math/bits"
+
+// bitmapArrayMax is the maximum number of rows of an array container, larger containers are stored
+// as bitsets.
+const bitmapArrayMax = 4096
+
+// bitmapContainerWords is the number of words of a bitset container (2^16 bits).
+const bitmapContainerWords = 1 << 16 / 64
+
+// BitmapContainer contains the rows of a Bitmap sharing the same upper bits.
+type BitmapContainer struct {
+	// Key is the row index shifted right by 16 bits.
+	Key int
+	// Array contains the sorted lower 16 bits of the rows of a sparse container (Bits is nil).
+	Array []uint16
+	// Bits is the bitset of a dense container.
+	Bits []uint64
+	// Count is the number of rows of the container.
+	Count int
+}
+
+// Bitmap is a compressed set of row indices in the style of roaring bitmaps: the rows are split
+// into containers of 2^16 rows, which are stored as sorted arrays if they are sparse or as bitsets
+// if they are dense.
+type Bitmap struct {
+	// Containers contains all non-empty containers sorted by their key.
+	Containers []BitmapContainer
+}
+
+// bitmapFromRows creates a bitmap from a list of row indices in ascending order.
+func bitmapFromRows(rows []int) Bitmap {
+	bitmap := Bitmap{}
+	for _, row := range rows {
+		bitmap.Add(row)
+	}
+	return bitmap
+}
+
+// Add adds the row rowIndex to the bitmap. Rows have to be added in ascending order.
+func (b *Bitmap) Add(rowIndex int) {
+	key := rowIndex >> 16
+	if len(b.Containers) == 0 || b.Containers[len(b.Containers)-1].Key != key {
+		b.Containers = append(b.Containers, BitmapContainer{Key: key})
+	}
+	b.Containers[len(b.Containers)-1].add(uint16(rowIndex))
+}
+
+// add adds the lower bits value to the container.
+func (c *BitmapContainer) add(value uint16) {
+	if c.Bits != nil {
+		if c.Bits[value/64]&(1<<(value%64)) == 0 {
+			c.Bits[value/64] |= 1 << (value % 64)
+			c.Count++
+		}
+		return
+	}
+
+	if len(c.Array) > 0 && c.Array[len(c.Array)-1] >= value {
+		return
+	}
+	c.Array = append(c.Array, value)
+	c.Count++
+
+	if c.Count > bitmapArrayMax {
+		c.toBits()
+	}
+}
+
+// toBits converts an array container into a bitset container.
+func (c *BitmapContainer) toBits() {
+	c.Bits = make([]uint64, bitmapContainerWords)
+	for _, value := range c.Array {
+		c.Bits[value/64] |= 1 << (value % 64)
+	}
+	c.Array = nil
+}
+
+// normalize sets Count and converts bitset containers with few rows into array containers.
+func (c *BitmapContainer) normalize() {
+	if c.Bits == nil {
+		c.Count = len(c.Array)
+		return
+	}
+
+	c.Count = 0
+	for _, word := range c.Bits {
+		c.Count += bits.OnesCount64(word)
+	}
+
+	if c.Count <= bitmapArrayMax {
+		c.Array = make([]uint16, 0, c.Count)
+		c.forEach(func(value uint16) {
+			c.Array = append(c.Array, value)
+		})
+		c.Bits = nil
+	}
+}
+
+// forEach calls fn for the lower bits of every row of the container in ascending order.
+func (c *BitmapContainer) forEach(fn func(value uint16)) {
+	if c.Bits == nil {
+		for _, value := range c.Array {
+			fn(value)
+		}
+		return
+	}
+
+	for wordIndex, word := range c.Bits {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			fn(uint16(wordIndex*64 + bit))
+			word &= word - 1
+		}
+	}
+}
+
+// contains reports whether the container contains the lower bits value.
+func (c *BitmapContainer) contains(value uint16) bool {
+	if c.Bits != nil {
+		return c.Bits[value/64]&(1<<(value%64)) != 0
+	}
+
+	low, high := 0, len(c.Array)
+	for low < high {
+		mid := (low + high) / 2
+		if c.Array[mid] < value {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low < len(c.Array) && c.Array[low] == value
+}
+
+// andContainers returns the intersection of two containers with the same key.
+func andContainers(a *BitmapContainer, b *BitmapContainer) BitmapContainer {
+	output := BitmapContainer{Key: a.Key}
+
+	switch {
+	case a.Bits != nil && b.Bits != nil:
+		output.Bits = make([]uint64, bitmapContainerWords)
+		for i := range output.Bits {
+			output.Bits[i] = a.Bits[i] & b.Bits[i]
+		}
+	case a.Bits != nil:
+		return andContainers(b, a)
+	default:
+		// a is an array container, so the output is at most as large as a
+		output.Array = []uint16{}
+		for _, value := range a.Array {
+			if b.contains(value) {
+				output.Array = append(output.Array, value)
+			}
+		}
+	}
+
+	output.normalize()
+	return output
+}
+
+// orContainers returns the union of two containers with the same key.
+func orContainers(a *BitmapContainer, b *BitmapContainer) BitmapContainer {
+	output := BitmapContainer{Key: a.Key}
+
+	if a.Bits == nil && b.Bits == nil && a.Count+b.Count <= bitmapArrayMax {
+		output.Array = make([]uint16, 0, a.Count+b.Count)
+		i, j := 0, 0
+		for i < len(a.Array) || j < len(b.Array) {
+			switch {
+			case j == len(b.Array) || (i < len(a.Array) && a.Array[i] < b.Array[j]):
+				output.Array = append(output.Array, a.Array[i])
+				i++
+			case i == len(a.Array) || b.Array[j] < a.Array[i]:
+				output.Array = append(output.Array, b.Array[j])
+				j++
+			default:
+				output.Array = append(output.Array, a.Array[i])
+				i++
+				j++
+			}
+		}
+		output.normalize()
+		return output
+	}
+
+	output.Bits = make([]uint64, bitmapContainerWords)
+	for _, c := range []*BitmapContainer{a, b} {
+		if c.Bits != nil {
+			for i, word := range c.Bits {
+				output.Bits[i] |= word
+			}
+			continue
+		}
+		for _, value := range c.Array {
+			output.Bits[value/64] |= 1 << (value % 64)
+		}
+	}
+
+	output.normalize()
+	return output
+}
+
+// And returns the intersection of the bitmaps.
+func (b Bitmap) And(other Bitmap) Bitmap {
+	output := Bitmap{Containers: []BitmapContainer{}}
+
+	i, j := 0, 0
+	for i < len(b.Containers) && j < len(other.Containers) {
+		switch {
+		case b.Containers[i].Key < other.Containers[j].Key:
+			i++
+		case b.Containers[i].Key > other.Containers[j].Key:
+			j++
+		default:
+			if container := andContainers(&b.Containers[i], &other.Containers[j]); container.Count > 0 {
+				output.Containers = append(output.Containers, container)
+			}
+			i++
+			j++
+		}
+	}
+
+	return output
+}
+
+// Or returns the union of the bitmaps.
+func (b Bitmap) Or(other Bitmap) Bitmap {
+	output := Bitmap{Containers: []BitmapContainer{}}
+
+	i, j := 0, 0
+	for i < len(b.Containers) || j < len(other.Containers) {
+		switch {
+		case j == len(other.Containers) || (i < len(b.Containers) && b.Containers[i].Key < other.Containers[j].Key):
+			output.Containers = append(output.Containers, b.Containers[i].clone())
+			i++
+		case i == len(b.Containers) || other.Containers[j].Key < b.Containers[i].Key:
+			output.Containers = append(output.Containers, other.Containers[j].clone())
+			j++
+		default:
+			output.Containers = append(output.Containers, orContainers(&b.Containers[i], &other.Containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return output
+}
+
+// Complement returns the bitmap of all rows in [0, numRows) which aren't contained in b.
+func (b Bitmap) Complement(numRows int) Bitmap {
+	output := Bitmap{Containers: []BitmapContainer{}}
+
+	next := 0
+	for key := 0; key<<16 < numRows; key++ {
+		container := BitmapContainer{Key: key, Bits: make([]uint64, bitmapContainerWords)}
+		end := min(numRows-key<<16, 1<<16)
+		for i := 0; i < end/64; i++ {
+			container.Bits[i] = ^uint64(0)
+		}
+		if end%64 != 0 {
+			container.Bits[end/64] = 1<<(end%64) - 1
+		}
+
+		for next < len(b.Containers) && b.Containers[next].Key < key {
+			next++
+		}
+		if next < len(b.Containers) && b.Containers[next].Key == key {
+			b.Containers[next].forEach(func(value uint16) {
+				container.Bits[value/64] &^= 1 << (value % 64)
+			})
+		}
+
+		if container.normalize(); container.Count > 0 {
+			output.Containers = append(output.Containers, container)
+		}
+	}
+
+	return output
+}
+
+// clone returns a copy of the container not sharing any memory with c.
+func (c BitmapContainer) clone() BitmapContainer {
+	if c.Bits != nil {
+		c.Bits = append([]uint64{}, c.Bits...)
+	} else {
+		c.Array = append([]uint16{}, c.Array...)
+	}
+	return c
+}
+
+// GetCardinality returns the number of rows of the bitmap.
+func (b Bitmap) GetCardinality() int {
+	count := 0
+	for _, container := range b.Containers {
+		count += container.Count
+	}
+	return count
+}
+
+// Rows returns the row indices of the bitmap in ascending order.
+func (b Bitmap) Rows() []int {
+	rows := make([]int, 0, b.GetCardinality())
+	for i := range b.Containers {
+		high := b.Containers[i].Key << 16
+		b.Containers[i].forEach(func(value uint16) {
+			rows = append(rows, high|int(value))
+		})
+	}
+	return rows
+}
+
+// memoryUsage returns the estimated number of bytes used by the bitmap.
+func (b Bitmap) memoryUsage() int {
+	size := sliceHeaderBytes + (cap(b.Containers)-len(b.Containers))*(2*sliceHeaderBytes+2*wordBytes)
+	for _, container := range b.Containers {
+		size += 2*sliceHeaderBytes + 2*wordBytes + cap(container.Array)*2 + cap(container.Bits)*wordBytes
+	}
+	return size
+}
@@ -0,0 +1,92 @@
+package csgo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBitmap(t *testing.T) {
+	// rows of sparse and dense containers
+	createRows := func(step int, offset int) []int {
+		rows := []int{}
+		for row := offset; row < 3*(1<<16)+100; row += step {
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	cases := [][]int{
+		{},
+		{0, 5, 1 << 16, 1<<16 + 3, 5 << 16},
+		createRows(3, 0),
+		createRows(2, 1),
+		createRows(97, 5),
+		createRows(1, 1<<16-10),
+	}
+
+	for caseID, rows := range cases {
+		bitmap := bitmapFromRows(rows)
+		if !reflect.DeepEqual(bitmap.Rows(), rows) || bitmap.GetCardinality() != len(rows) {
+			t.Errorf("test case %d: rows do not match", caseID)
+		}
+	}
+
+	for caseID, rows := range cases {
+		contained := make([]bool, 6<<16)
+		for _, row := range rows {
+			contained[row] = true
+		}
+
+		for _, numRows := range []int{0, 100, 1<<16 + 5, 4 << 16} {
+			expected := []int{}
+			for row := 0; row < numRows; row++ {
+				if !contained[row] {
+					expected = append(expected, row)
+				}
+			}
+
+			if complement := bitmapFromRows(rows).Complement(numRows).Rows(); !reflect.DeepEqual(complement, expected) {
+				t.Errorf("test case %d: complement within %d rows does not match", caseID, numRows)
+			}
+		}
+	}
+
+	for i, left := range cases {
+		for j, right := range cases {
+			inRight := make([]bool, 6<<16)
+			for _, row := range right {
+				inRight[row] = true
+			}
+
+			intersection := []int{}
+			union := append([]int{}, right...)
+			for _, row := range left {
+				if inRight[row] {
+					intersection = append(intersection, row)
+				} else {
+					union = append(union, row)
+				}
+			}
+			sort.Ints(union)
+
+			leftBitmap, rightBitmap := bitmapFromRows(left), bitmapFromRows(right)
+			if rows := leftBitmap.And(rightBitmap).Rows(); !reflect.DeepEqual(rows, intersection) {
+				t.Errorf("intersection of test cases %d and %d does not match", i, j)
+			}
+			if rows := leftBitmap.Or(rightBitmap).Rows(); !reflect.DeepEqual(rows, union) {
+				t.Errorf("union of test cases %d and %d does not match", i, j)
+			}
+		}
+	}
+
+	// dense containers are stored as bitsets, sparse containers as arrays
+	bitmap := bitmapFromRows(cases[2])
+	if bitmap.Containers[0].Bits == nil || len(bitmap.Containers) != 4 || bitmap.Containers[3].Bits != nil {
+		t.Error("unexpected container types")
+	}
+	sparse := bitmap.And(bitmapFromRows(cases[4]))
+	if sparse.Containers[0].Bits != nil || sparse.Containers[0].Count != len(sparse.Containers[0].Array) {
+		t.Error("sparse intersection was not converted into an array container")
+	}
+}
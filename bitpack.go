@@ -0,0 +1,114 @@
+package csgo
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// BitPackedDataStore is a DataStore for ungrouped INT data storing every value using the minimum
+// bit width needed for the largest (zigzag encoded) value added so far.
+type BitPackedDataStore struct {
+	// Width is the number of bits per value.
+	Width int
+	// Bits contains the zigzag encoded values.
+	Bits    BitStream
+	NumRows int
+}
+
+// NewBitPackedDataStore creates a new BitPackedDataStore.
+func NewBitPackedDataStore() DataStore {
+	return &BitPackedDataStore{}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *BitPackedDataStore) GetDataType() DataTypes {
+	return INT
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *BitPackedDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// AddRow adds a new row to the column. If the value doesn't fit into the current bit width, all
+// values get repacked using the larger width.
+func (ds *BitPackedDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValue, rightType := value.(int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	encoded := zigzag(intValue)
+	if width := bits.Len64(encoded); width > ds.Width {
+		ds.Bits = ds.Bits.repack(ds.NumRows, ds.Width, width)
+		ds.Width = width
+	}
+
+	ds.Bits.Write(encoded, ds.Width)
+	ds.NumRows++
+	return ds.NumRows - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *BitPackedDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= ds.NumRows {
+		return nil, errors.New("index out of bounds")
+	}
+	return unzigzag(ds.Bits.Read(rowIndex*ds.Width, ds.Width)), nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *BitPackedDataStore) GetNumRows() int {
+	return ds.NumRows
+}
+
+// AddRows adds all values to the column and returns the index of the first added row. The values
+// are repacked at most once.
+func (ds *BitPackedDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValues, rightType := values.([]int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	width := ds.Width
+	for _, value := range intValues {
+		width = max(width, bits.Len64(zigzag(value)))
+	}
+	if width > ds.Width {
+		ds.Bits = ds.Bits.repack(ds.NumRows, ds.Width, width)
+		ds.Width = width
+	}
+
+	firstIndex := ds.NumRows
+	for _, value := range intValues {
+		ds.Bits.Write(zigzag(value), ds.Width)
+	}
+	ds.NumRows += len(intValues)
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end).
+func (ds *BitPackedDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, ds.NumRows); err != nil {
+		return nil, err
+	}
+
+	values := make([]int, end-start)
+	for i := range values {
+		values[i] = unzigzag(ds.Bits.Read((start+i)*ds.Width, ds.Width))
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *BitPackedDataStore) MemoryUsage() int {
+	return 2*wordBytes + ds.Bits.memoryUsage()
+}
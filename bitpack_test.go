@@ -0,0 +1,46 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createBitPackedDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewBitPackedDataStore(), 1, 2, 3),
+		fillDataStore(NewBitPackedDataStore(), 0, -1, 1<<62, -1<<63),
+		NewBitPackedDataStore(),
+	}
+}
+
+func TestBitPackedDataStoreAddRow(t *testing.T) {
+	for _, ds := range createBitPackedDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestBitPackedDataStoreGetRow(t *testing.T) {
+	for _, ds := range createBitPackedDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestBitPackedDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createBitPackedDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestBitPackedDataStoreAddRowsGetRange(t *testing.T) {
+	values := []int{3, 0, 7, -4, 1000, 5, 12}
+	for _, ds := range createBitPackedDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	// 0..7 fit into 4 bits after zigzag encoding
+	ds := fillDataStore(NewBitPackedDataStore(), 0, 1, 2, 3, 4, 5, 6, 7).(*BitPackedDataStore)
+	data, _ := ds.GetRange(0, 8)
+	if ds.Width != 4 || len(ds.Bits.Words) != 1 || !reflect.DeepEqual(data, []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("unexpected packing: width %d, %d words, data %v", ds.Width, len(ds.Bits.Words), data)
+	}
+}
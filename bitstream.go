@@ -0,0 +1,65 @@
+package csgo
+
+// BitStream is a sequence of bits packed into 64 bit words (least significant bits first). It is
+// used by the bit-level encodings to store values of arbitrary bit widths.
+type BitStream struct {
+	// Words contains the bits of the stream.
+	Words []uint64
+	// NumBits is the number of bits written to the stream.
+	NumBits int
+}
+
+// Write appends the lowest width bits of value to the stream (0 <= width <= 64).
+func (bs *BitStream) Write(value uint64, width int) {
+	if width == 0 {
+		return
+	}
+	if width < 64 {
+		value &= 1<<uint(width) - 1
+	}
+
+	offset := uint(bs.NumBits % 64)
+	if offset == 0 {
+		bs.Words = append(bs.Words, 0)
+	}
+	bs.Words[len(bs.Words)-1] |= value << offset
+
+	// bits not fitting into the current word are written to the next one
+	if written := 64 - int(offset); written < width {
+		bs.Words = append(bs.Words, value>>uint(written))
+	}
+	bs.NumBits += width
+}
+
+// Read returns width bits starting at the bit position pos (0 <= width <= 64).
+func (bs *BitStream) Read(pos int, width int) uint64 {
+	if width == 0 {
+		return 0
+	}
+
+	word, offset := pos/64, uint(pos%64)
+	value := bs.Words[word] >> offset
+	if read := 64 - int(offset); read < width {
+		value |= bs.Words[word+1] << uint(read)
+	}
+
+	if width < 64 {
+		value &= 1<<uint(width) - 1
+	}
+	return value
+}
+
+// memoryUsage returns the number of bytes used by the stream.
+func (bs *BitStream) memoryUsage() int {
+	return sliceHeaderBytes + wordBytes + cap(bs.Words)*wordBytes
+}
+
+// repack returns a copy of the first numValues values of a stream of fixed width values using the
+// new width.
+func (bs *BitStream) repack(numValues int, width int, newWidth int) BitStream {
+	packed := BitStream{Words: make([]uint64, 0, (numValues*newWidth+63)/64+1)}
+	for i := 0; i < numValues; i++ {
+		packed.Write(bs.Read(i*width, width), newWidth)
+	}
+	return packed
+}
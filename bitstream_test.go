@@ -0,0 +1,36 @@
+package csgo
+
+import "testing"
+
+func TestBitStream(t *testing.T) {
+	type entry struct {
+		value uint64
+		width int
+	}
+	entries := []entry{{5, 3}, {0, 0}, {1<<63 | 1, 64}, {0x1ff, 9}, {0, 1}, {1<<40 - 1, 40}, {7, 3}, {1<<63 - 1, 63}}
+
+	bs := BitStream{}
+	for _, e := range entries {
+		bs.Write(e.value, e.width)
+	}
+
+	pos := 0
+	for entryID, e := range entries {
+		if value := bs.Read(pos, e.width); value != e.value {
+			t.Errorf("entry %d: expected %#x, got %#x", entryID, e.value, value)
+		}
+		pos += e.width
+	}
+
+	if bs.NumBits != pos || len(bs.Words) != (pos+63)/64 {
+		t.Errorf("unexpected stream size: %d bits in %d words", bs.NumBits, len(bs.Words))
+	}
+
+	// values are truncated to their width
+	bs = BitStream{}
+	bs.Write(0xff, 4)
+	bs.Write(0, 4)
+	if bs.Read(0, 8) != 0x0f {
+		t.Errorf("expected 0x0f, got %#x", bs.Read(0, 8))
+	}
+}
@@ -7,56 +7,70 @@ import (
 
 // NewColumn creates a new column according to the given AttrInfo
 func NewColumn(sig AttrInfo) Column {
-	col := Column{Signature: sig}
+	col := Column{Signature: sig, Zones: newZoneMap(sig), Indexes: newIndexes(sig)}
+	// index flags only affect the column, not the DataStore
+	flags := sig.Flags.dataFlags()
 
-	switch sig.Enc {
-	case NOCOMP:
-		col.Data = NewBasicDataStore(sig.Type, sig.Flags)
+	switch sig.Enc.Outer() {
 	case RLE:
-		col.Data = NewRLEDataStore(sig.Type, sig.Flags)
+		col.Data = NewRLEDataStore(sig.Type, flags)
 	case DICT:
-		col.Data = NewDictEncodedDataStore(sig.Type, sig.Flags, NOCOMP)
+		col.Data = NewDictEncodedDataStore(sig.Type, flags, sig.Enc.Inner())
+	case FOR, DELTA, BITPACK:
+		// these encodings only support ungrouped integers
+		if sig.Type == INT && flags == 0 {
+			col.Data = newIntDataStore(sig.Enc.Outer())
+		} else {
+			col.Data = NewBasicDataStore(sig.Type, flags)
+		}
+	case XOR:
+		// only ungrouped floats can be XOR compressed
+		if sig.Type == FLOAT && flags == 0 {
+			col.Data = NewXORDataStore()
+		} else {
+			col.Data = NewBasicDataStore(sig.Type, flags)
+		}
+	case FRONTCODING, STRINGHEAP:
+		// these encodings only support ungrouped strings
+		if sig.Type == STRING && flags == 0 && sig.Enc.Outer() == FRONTCODING {
+			col.Data = NewFrontCodedDataStore()
+		} else if sig.Type == STRING && flags == 0 {
+			col.Data = NewStringHeapDataStore()
+		} else {
+			col.Data = NewBasicDataStore(sig.Type, flags)
+		}
 	default:
-		col.Data = NewBasicDataStore(sig.Type, sig.Flags)
+		// ungrouped values don't need to be boxed
+		if flags == 0 {
+			col.Data = NewTypedDataStore(sig.Type)
+		} else {
+			col.Data = NewBasicDataStore(sig.Type, flags)
+		}
 	}
 	return col
 }
 
+// newIntDataStore creates a DataStore for ungrouped INT data using the given encoding.
+func newIntDataStore(enc Compression) DataStore {
+	switch enc {
+	case RLE:
+		return NewRLEDataStore(INT, 0)
+	case FOR:
+		return NewFORDataStore()
+	case DELTA:
+		return NewDeltaDataStore()
+	case BITPACK:
+		return NewBitPackedDataStore()
+	}
+	return NewTypedDataStore(INT)
+}
+
 // NewColumnWithData creates a new Column according to the given AttrInfo and fills it with the values in data (must be a slice of the corresponding type).
+// It panics if the values can't be added, e.g. because they violate the SORTED or UNIQUE constraint of the column.
 func NewColumnWithData(sig AttrInfo, data interface{}) Column {
 	col := NewColumn(sig)
-
-	switch {
-	case sig.Flags == 0:
-		switch sig.Type {
-		case INT:
-			for _, val := range data.([]int) {
-				col.AddRow(INT, val)
-			}
-		case FLOAT:
-			for _, val := range data.([]float64) {
-				col.AddRow(FLOAT, val)
-			}
-		case STRING:
-			for _, val := range data.([]string) {
-				col.AddRow(STRING, val)
-			}
-		}
-	case sig.Flags&GROUPED == GROUPED && sig.Flags&NULLABLE == 0:
-		switch sig.Type {
-		case INT:
-			for _, val := range data.([][]int) {
-				col.AddRow(INT, val)
-			}
-		case FLOAT:
-			for _, val := range data.([][]float64) {
-				col.AddRow(FLOAT, val)
-			}
-		case STRING:
-			for _, val := range data.([][]string) {
-				col.AddRow(STRING, val)
-			}
-		}
+	if _, err := col.AddRows(sig.Type, data); err != nil {
+		panic(err)
 	}
 	return col
 }
@@ -64,23 +78,22 @@ func NewColumnWithData(sig AttrInfo, data interface{}) Column {
 // ImportRow imports a string value into the column.
 // Useful when parsing text input
 func (col *Column) ImportRow(field string) (int, error) {
-	switch col.Signature.Type {
-	case INT:
-		value, err := strconv.Atoi(field)
-		if err != nil {
-			return -1, err
-		}
-		return col.AddRow(INT, value)
+	value, err := parseValue(col.Signature.Type, field)
+	if err != nil {
+		return -1, err
+	}
+	return col.AddRow(col.Signature.Type, value)
+}
 
+// parseValue converts a string into a value of the given type.
+func parseValue(typ DataTypes, field string) (interface{}, error) {
+	switch typ {
+	case INT:
+		return strconv.Atoi(field)
 	case FLOAT:
-		value, err := strconv.ParseFloat(field, 64)
-		if err != nil {
-			return -1, err
-		}
-		return col.AddRow(FLOAT, value)
-
+		return strconv.ParseFloat(field, 64)
 	case STRING:
-		return col.AddRow(STRING, field)
+		return field, nil
 	}
 
 	// shouldn't happen
@@ -89,7 +102,17 @@ func (col *Column) ImportRow(field string) (int, error) {
 
 // AddRow adds a row with the specified value.
 // Currently, the value gets appended at the end of the Data slice. This might change in the future.
-func (col *Column) AddRow(typ DataTypes, value interface{}) (index int, err error) {
+// Columns backed by a position list get materialized first (see Materialize). Values violating
+// the SORTED or UNIQUE constraint of the column are rejected (see checkValues).
+func (col *Column) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if err := col.checkValues([]interface{}{value}); err != nil {
+		return -1, err
+	}
+	return col.appendRow(typ, value)
+}
+
+// appendRow adds a row with the specified value without checking the constraints of the column.
+func (col *Column) appendRow(typ DataTypes, value interface{}) (index int, err error) {
 	// catch conversion panics (typ does not match value)
 	defer func() {
 		if r := recover(); r != nil {
@@ -97,7 +120,130 @@ func (col *Column) AddRow(typ DataTypes, value interface{}) (index int, err erro
 			err = fmt.Errorf("%#v", r)
 		}
 	}()
-	return (col.Data.(DataStore)).AddRow(typ, value)
+	if *col, err = col.materialize(); err != nil {
+		return -1, err
+	}
+
+	index, err = (col.Data.(DataStore)).AddRow(typ, value)
+	if err == nil {
+		col.Zones.add(index, value)
+		for _, colIndex := range col.Indexes {
+			colIndex.Add(index, value)
+		}
+	}
+	return index, err
+}
+
+// AddRows adds a row for every value in values (a slice of the type matching the column) and
+// returns the index of the first added row. Columns backed by a position list get materialized
+// first (see Materialize). If a value violates the SORTED or UNIQUE constraint of the column, no
+// row is added.
+func (col *Column) AddRows(typ DataTypes, values interface{}) (int, error) {
+	var err error
+	if *col, err = col.materialize(); err != nil {
+		return -1, err
+	}
+
+	// sorted columns and indexes need the single values (nil for grouped or mismatching values)
+	var boxed []interface{}
+	if col.Signature.Flags&(SORTED|UNIQUE) != 0 || len(col.Indexes) > 0 {
+		boxed, _ = boxValues(col.Signature.Type, 0, values)
+	}
+
+	if err := col.checkValues(boxed); err != nil {
+		return -1, err
+	}
+
+	firstIndex, err := (col.Data.(DataStore)).AddRows(typ, values)
+	if err == nil {
+		col.Zones.addRows(firstIndex, values)
+		for i, value := range boxed {
+			for _, colIndex := range col.Indexes {
+				colIndex.Add(firstIndex+i, value)
+			}
+		}
+	}
+	return firstIndex, err
+}
+
+// checkValues returns an error if appending values would break the order of a SORTED column or
+// duplicate a value of a UNIQUE column. Composite primary keys (see PRIMARYKEY) span several
+// columns and are only checked by Relation.AddRow.
+func (col *Column) checkValues(values []interface{}) error {
+	if err := col.checkSorted(values); err != nil {
+		return err
+	}
+	return col.checkUnique(values)
+}
+
+// checkUnique returns an error if appending values would duplicate a value of a UNIQUE column.
+// Values not matching the column type are left to the DataStore.
+func (col *Column) checkUnique(values []interface{}) error {
+	if col.Signature.Flags&UNIQUE == 0 || col.Signature.Flags&GROUPED != 0 {
+		return nil
+	}
+
+	added := map[interface{}]bool{}
+	for _, value := range values {
+		if !hasType(col.Signature.Type, value) {
+			continue
+		}
+
+		rows, err := col.selectRows(EQ, value, 1)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 || added[value] {
+			return fmt.Errorf("duplicate value %v in unique column %s", value, col.Signature.Name)
+		}
+		added[value] = true
+	}
+	return nil
+}
+
+// checkSorted returns an error if appending values would break the order of a SORTED column.
+// NaN values are rejected, as they can't be ordered. Values not matching the column type are left
+// to the DataStore.
+func (col *Column) checkSorted(values []interface{}) error {
+	if col.Signature.Flags&SORTED == 0 || col.Signature.Flags&GROUPED != 0 {
+		return nil
+	}
+
+	less := compFuncs[col.Signature.Type][LT]
+	var last interface{}
+	if numRows := col.GetNumRows(); numRows > 0 {
+		var err error
+		if last, err = col.GetRow(numRows - 1); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range values {
+		if !hasType(col.Signature.Type, value) {
+			return nil
+		}
+		if isNaN(value) {
+			return fmt.Errorf("NaN can't be added to sorted column %s", col.Signature.Name)
+		}
+		if last != nil && less(value, last) {
+			return fmt.Errorf("value %v breaks the order of sorted column %s", value, col.Signature.Name)
+		}
+		last = value
+	}
+	return nil
+}
+
+// containsNaN reports whether a FLOAT column contains NaN values.
+func (col *Column) containsNaN() bool {
+	if col.Signature.Type != FLOAT || col.Signature.Flags&GROUPED != 0 {
+		return false
+	}
+	for row := 0; row < col.GetNumRows(); row++ {
+		if value, err := col.GetRow(row); err == nil && isNaN(value) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetRow returns the value in the given row.
@@ -105,6 +251,11 @@ func (col Column) GetRow(index int) (interface{}, error) {
 	return (col.Data.(DataStore)).GetRow(index)
 }
 
+// GetRange returns the values of the rows [start, end) as a slice of the type matching the column.
+func (col Column) GetRange(start int, end int) (interface{}, error) {
+	return (col.Data.(DataStore)).GetRange(start, end)
+}
+
 // GetNumRows returns the number of rows present in the Column.
 func (col Column) GetNumRows() int {
 	return (col.Data.(DataStore)).GetNumRows()
@@ -112,29 +263,43 @@ func (col Column) GetNumRows() int {
 
 // GetRawData rturns a slice of all values present in the column (in index order).
 func (col Column) GetRawData() interface{} {
-	switch col.Signature.Type {
-	case INT:
-		rawValues := []int{}
-		for i := 0; i < col.GetNumRows(); i++ {
-			value, _ := col.GetRow(i)
-			rawValues = append(rawValues, value.(int))
-		}
-		return rawValues
-	case FLOAT:
-		rawValues := []float64{}
-		for i := 0; i < col.GetNumRows(); i++ {
-			value, _ := col.GetRow(i)
-			rawValues = append(rawValues, value.(float64))
-		}
-		return rawValues
-	case STRING:
-		rawValues := []string{}
-		for i := 0; i < col.GetNumRows(); i++ {
-			value, _ := col.GetRow(i)
-			rawValues = append(rawValues, value.(string))
-		}
-		return rawValues
+	data, err := col.GetRange(0, col.GetNumRows())
+	if err != nil {
+		panic(err)
 	}
+	return data
+}
+
+// newColumnView creates a column with the given signature, which references the rows positions of
+// source instead of copying their values (see PositionListDataStore).
+func newColumnView(sig AttrInfo, source *Column, positions []int) Column {
+	return Column{Signature: sig, Data: NewPositionListDataStore(source.Data.(DataStore), positions)}
+}
 
-	panic("unknown data type")
+// Materialize returns a column storing its own values in the encoding given by its signature.
+// Columns which aren't backed by a position list are returned unchanged. It panics if the
+// referenced values can't be read or copied.
+func (col Column) Materialize() Column {
+	output, err := col.materialize()
+	if err != nil {
+		panic(err)
+	}
+	return output
+}
+
+// materialize is Materialize returning the error instead of panicking.
+func (col Column) materialize() (Column, error) {
+	if _, isView := col.Data.(*PositionListDataStore); !isView {
+		return col, nil
+	}
+
+	values, err := col.GetRange(0, col.GetNumRows())
+	if err != nil {
+		return col, err
+	}
+	output := NewColumn(col.Signature)
+	if _, err := output.AddRows(col.Signature.Type, values); err != nil {
+		return col, err
+	}
+	return output, nil
 }
@@ -173,3 +173,53 @@ func TestColumnImportRow(t *testing.T) {
 		}
 	}
 }*/
+
+func TestNewColumnLayeredEncoding(t *testing.T) {
+	cases := []struct {
+		enc       Compression
+		outer     interface{}
+		codeStore interface{}
+	}{
+		{enc: DICT, outer: &DictEncodedDataStore{}, codeStore: &TypedDataStore[int]{}},
+		{enc: DICTRLE, outer: &DictEncodedDataStore{}, codeStore: &RLEDataStore{}},
+		{enc: DICTFOR, outer: &DictEncodedDataStore{}, codeStore: &FORDataStore{}},
+		{enc: DICT.Layered(RLE), outer: &DictEncodedDataStore{}, codeStore: &RLEDataStore{}},
+		{enc: FOR, outer: &FORDataStore{}},
+	}
+
+	values := []int{7, 7, 7, 3, 3, 9, 7}
+	for testCaseID, testCase := range cases {
+		col := NewColumnWithData(AttrInfo{"col", INT, testCase.enc, 0}, values)
+
+		if reflect.TypeOf(col.Data) != reflect.TypeOf(testCase.outer) {
+			t.Errorf("test case %d: expected %T, got %T", testCaseID, testCase.outer, col.Data)
+		}
+		if ds, isDict := col.Data.(*DictEncodedDataStore); isDict && reflect.TypeOf(ds.Data) != reflect.TypeOf(testCase.codeStore) {
+			t.Errorf("test case %d: expected codes in %T, got %T", testCaseID, testCase.codeStore, ds.Data)
+		}
+		if !reflect.DeepEqual(col.GetRawData(), values) {
+			t.Errorf("test case %d: expected %v, got %v", testCaseID, values, col.GetRawData())
+		}
+	}
+
+	if DICTRLE.Outer() != DICT || DICTRLE.Inner() != RLE || RLE.Inner() != NOCOMP {
+		t.Error("layered encoding can not be decomposed")
+	}
+}
+
+func TestNewColumnIntEncodings(t *testing.T) {
+	values := []int{10, 11, 13, 13, 20, 8}
+
+	for _, enc := range []Compression{DELTA, BITPACK, DICT.Layered(BITPACK), DICT.Layered(DELTA)} {
+		col := NewColumnWithData(AttrInfo{"col", INT, enc, 0}, values)
+		if !reflect.DeepEqual(col.GetRawData(), values) {
+			t.Errorf("encoding %v: expected %v, got %v", enc, values, col.GetRawData())
+		}
+	}
+
+	// only ungrouped integers can be bit packed
+	col := NewColumn(AttrInfo{"col", STRING, BITPACK, 0})
+	if _, isBasic := col.Data.(*BasicDataStore); !isBasic {
+		t.Errorf("expected a BasicDataStore for a bit packed STRING column, got %T", col.Data)
+	}
+}
@@ -56,6 +56,9 @@ func test_case() {
 // to enable cpu profiling
 var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
 
+// to print the memory usage of all relations after loading
+var sizereport = flag.Bool("sizereport", false, "print the memory usage of all columns after loading")
+
 func main() {
 
 	//test_case()
@@ -106,13 +109,13 @@ func main() {
 	cs := ColumnStore{}
 	tblPartSupp := cs.CreateRelation("PARTSUPP", []AttrInfo{
 		{Name: "PARTKEY", Type: INT, Enc: NOCOMP},
-		{Name: "SUPPKEY", Type: INT, Enc: NOCOMP},
+		{Name: "SUPPKEY", Type: INT, Enc: NOCOMP, Flags: HASHINDEX},
 		{Name: "AVAILQTY", Type: INT, Enc: NOCOMP},
 		{Name: "SUPPLYCOST", Type: FLOAT, Enc: NOCOMP},
 		{Name: "COMMENT", Type: STRING, Enc: NOCOMP},
 	})
 	tblSupplier := cs.CreateRelation("SUPPLIER", []AttrInfo{
-		{"SUPPKEY", INT, NOCOMP, 0},
+		{"SUPPKEY", INT, NOCOMP, PRIMARYKEY},
 		{"NAME", STRING, NOCOMP, 0},
 		{"ADDRESS", STRING, NOCOMP, 0},
 		{"NATIONKEY", INT, NOCOMP, 0},
@@ -121,7 +124,7 @@ func main() {
 		{"COMMENT", STRING, NOCOMP, 0},
 	})
 	tblPart := cs.CreateRelation("PART", []AttrInfo{
-		{"PARTKEY", INT, NOCOMP, 0},
+		{"PARTKEY", INT, NOCOMP, PRIMARYKEY},
 		{"NAME", STRING, NOCOMP, 0},
 		{"MFGR", STRING, NOCOMP, 0},
 		{"BRAND", STRING, NOCOMP, 0},
@@ -132,9 +135,31 @@ func main() {
 		{"COMMENT", STRING, NOCOMP, 0},
 	})
 
-	tblPartSupp.Load("partsupp.tbl", '|')
+	for _, fk := range []ForeignKey{
+		{Relation: "PARTSUPP", Columns: []string{"PARTKEY"}, Referenced: "PART"},
+		{Relation: "PARTSUPP", Columns: []string{"SUPPKEY"}, Referenced: "SUPPLIER"},
+	} {
+		if err := cs.DeclareForeignKey(fk); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// the referenced relations are loaded first, so the foreign keys can be validated on load
 	tblSupplier.Load("supplier.tbl", '|')
 	tblPart.Load("part.tbl", '|')
+	reports, err := cs.Load("PARTSUPP", "partsupp.tbl", '|')
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, report := range reports {
+		if len(report.Rows) > 0 {
+			fmt.Println(report)
+		}
+	}
+
+	if *sizereport {
+		cs.PrintMemoryUsage()
+	}
 
 	negativeSuppliers := tblSupplier.Scan([]AttrInfo{{"SUPPKEY", INT, NOCOMP, 0}, {"ACCTBAL", FLOAT, NOCOMP, 0}}).Select(AttrInfo{"ACCTBAL", FLOAT, NOCOMP, 0}, LT, float64(0.0))
 	//negativeSuppliers.Print()
@@ -1,5 +1,10 @@
 package csgo
 
+import (
+	"errors"
+	"fmt"
+)
+
 // DataStore is an internal interface for storing column data
 type DataStore interface {
 	// GetDataType returns the type of the stored data.
@@ -12,4 +17,231 @@ type DataStore interface {
 	GetRow(rowIndex int) (interface{}, error)
 	// GetNumRows returns the number of rows currently included in this column
 	GetNumRows() int
+	// AddRows appends all values (a slice of the type matching the stored data, e.g. []int or
+	// [][]int for grouped INT data) and returns the index of the first added row. If any value
+	// doesn't match, no row is added.
+	AddRows(typ DataTypes, values interface{}) (int, error)
+	// GetRange returns the values of the rows [start, end) as a slice of the type matching the
+	// stored data (see AddRows).
+	GetRange(start int, end int) (interface{}, error)
+	// MemoryUsage returns the estimated number of bytes used by the DataStore, including the
+	// payload of all values and the overhead of the encoding.
+	MemoryUsage() int
+}
+
+// PredicateEvaluator is an optional DataStore capability for evaluating predicates directly on the
+// encoded data instead of decoding every single row.
+type PredicateEvaluator interface {
+	// SelectRows appends the indices of all rows in [start, end) whose value satisfies the predicate
+	// (value comp compVal) to out in ascending order.
+	SelectRows(start int, end int, comp Comparison, compVal interface{}, out []int) ([]int, error)
+}
+
+// checkPredicate returns the CompFunc for a predicate on a DataStore of the given type and flags.
+func checkPredicate(typ DataTypes, flags ColumnFlags, comp Comparison) (CompFunc, error) {
+	if flags&GROUPED != 0 {
+		return nil, errors.New("predicates can not be evaluated on grouped data")
+	}
+
+	compFunc, found := compFuncs[typ][comp]
+	if !found {
+		return nil, errors.New("comparison func not found")
+	}
+	return compFunc, nil
+}
+
+// scanRuns calls fn for every run of equal values overlapping the rows [start, end) of ds, clipped
+// to that range. Run length encoded data is scanned run by run, any other data row by row.
+func scanRuns(ds DataStore, start int, end int, fn func(runStart int, runEnd int, value interface{})) error {
+	if err := checkRange(start, end, ds.GetNumRows()); err != nil {
+		return err
+	}
+
+	switch store := ds.(type) {
+	case *RLEDataStore:
+		return store.scanEntries(start, end, func(runStart int, runEnd int, entry int) {
+			fn(runStart, runEnd, valueAt(store.Values, entry))
+		})
+	case *TypedDataStore[int]:
+		for row, value := range store.Values[start:end] {
+			fn(start+row, start+row+1, value)
+		}
+		return nil
+	case *BasicDataStore:
+		for row, value := range store.Values[start:end] {
+			fn(start+row, start+row+1, value)
+		}
+		return nil
+	}
+
+	for row := start; row < end; row++ {
+		value, err := ds.GetRow(row)
+		if err != nil {
+			return err
+		}
+		fn(row, row+1, value)
+	}
+	return nil
+}
+
+// checkRange returns an error if [start, end) isn't a valid row range of a DataStore with numRows rows.
+func checkRange(start int, end int, numRows int) error {
+	if start < 0 || end > numRows || start > end {
+		return fmt.Errorf("rows [%d, %d) out of bounds", start, end)
+	}
+	return nil
+}
+
+// hasType reports whether value is a single (ungrouped) value of the data type typ.
+func hasType(typ DataTypes, value interface{}) bool {
+	switch value.(type) {
+	case int:
+		return typ == INT
+	case float64:
+		return typ == FLOAT
+	case string:
+		return typ == STRING
+	}
+	return false
+}
+
+// hasGroupedType reports whether value is a grouped value (slice) of the data type typ.
+func hasGroupedType(typ DataTypes, value interface{}) bool {
+	switch value.(type) {
+	case []int:
+		return typ == INT
+	case []float64:
+		return typ == FLOAT
+	case []string:
+		return typ == STRING
+	}
+	return false
+}
+
+// boxValues checks that values is a slice matching the data type and flags of a DataStore and
+// returns its elements as []interface{} (see AddRows).
+func boxValues(typ DataTypes, flags ColumnFlags, values interface{}) ([]interface{}, error) {
+	switch {
+	case flags == 0:
+		switch typed := values.(type) {
+		case []int:
+			if typ == INT {
+				return boxSlice(typed), nil
+			}
+		case []float64:
+			if typ == FLOAT {
+				return boxSlice(typed), nil
+			}
+		case []string:
+			if typ == STRING {
+				return boxSlice(typed), nil
+			}
+		}
+	case flags&GROUPED == GROUPED && flags&NULLABLE == 0:
+		switch typed := values.(type) {
+		case [][]int:
+			if typ == INT {
+				return boxSlice(typed), nil
+			}
+		case [][]float64:
+			if typ == FLOAT {
+				return boxSlice(typed), nil
+			}
+		case [][]string:
+			if typ == STRING {
+				return boxSlice(typed), nil
+			}
+		}
+	}
+	return nil, errors.New("type mismatch")
+}
+
+// unboxValues converts boxed values of a DataStore into a slice of the matching type (see GetRange).
+func unboxValues(typ DataTypes, flags ColumnFlags, values []interface{}) (interface{}, error) {
+	switch {
+	case flags == 0:
+		switch typ {
+		case INT:
+			return unboxSlice[int](values), nil
+		case FLOAT:
+			return unboxSlice[float64](values), nil
+		case STRING:
+			return unboxSlice[string](values), nil
+		}
+	case flags&GROUPED == GROUPED && flags&NULLABLE == 0:
+		switch typ {
+		case INT:
+			return unboxSlice[[]int](values), nil
+		case FLOAT:
+			return unboxSlice[[]float64](values), nil
+		case STRING:
+			return unboxSlice[[]string](values), nil
+		}
+	}
+	return nil, errors.New("unknown data type")
+}
+
+func boxSlice[T any](values []T) []interface{} {
+	output := make([]interface{}, len(values))
+	for i, value := range values {
+		output[i] = value
+	}
+	return output
+}
+
+func unboxSlice[T any](values []interface{}) []T {
+	output := make([]T, len(values))
+	for i, value := range values {
+		output[i] = value.(T)
+	}
+	return output
+}
+
+// newValueStore creates the DataStore keeping the distinct values of a dictionary or the run
+// values of run length encoded data. Ungrouped values are stored unboxed (see TypedDataStore).
+func newValueStore(typ DataTypes, flags ColumnFlags) DataStore {
+	if flags == 0 {
+		return NewTypedDataStore(typ)
+	}
+	return NewBasicDataStore(typ, flags)
+}
+
+// valueAt returns the value of the row rowIndex of a value store (see newValueStore), which is
+// known to exist.
+func valueAt(ds DataStore, rowIndex int) interface{} {
+	value, _ := ds.GetRow(rowIndex)
+	return value
+}
+
+// gatherValues returns the values of the given rows of a value store (see newValueStore) as a
+// slice of the type matching typ and flags.
+func gatherValues(ds DataStore, typ DataTypes, flags ColumnFlags, rows []int) (interface{}, error) {
+	switch store := ds.(type) {
+	case *TypedDataStore[int]:
+		return gather(store.Values, rows), nil
+	case *TypedDataStore[float64]:
+		return gather(store.Values, rows), nil
+	case *TypedDataStore[string]:
+		return gather(store.Values, rows), nil
+	case *BasicDataStore:
+		return unboxValues(typ, flags, gather(store.Values, rows))
+	}
+
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		value, err := ds.GetRow(row)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return unboxValues(typ, flags, values)
+}
+
+func gather[T any](values []T, rows []int) []T {
+	output := make([]T, len(rows))
+	for i, row := range rows {
+		output[i] = values[row]
+	}
+	return output
 }
@@ -144,3 +144,64 @@ func testDataStoreGetDataType(ds DataStore, dataType DataTypes, t *testing.T) {
 		t.Fail()
 	}
 }
+
+func testDataStoreSelectRows(ds DataStore, compVal interface{}, t *testing.T) {
+	evaluator, ok := ds.(PredicateEvaluator)
+	if !ok {
+		t.Fatalf("%T does not implement PredicateEvaluator", ds)
+	}
+
+	numRows := ds.GetNumRows()
+	ranges := [][2]int{{0, numRows}, {1, numRows - 1}, {numRows / 2, numRows}, {2, 2}}
+
+	for _, comp := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+		for _, rowRange := range ranges {
+			expected := []int{}
+			for row := rowRange[0]; row < rowRange[1]; row++ {
+				value, _ := ds.GetRow(row)
+				if compFuncs[ds.GetDataType()][comp](value, compVal) {
+					expected = append(expected, row)
+				}
+			}
+
+			rows, err := evaluator.SelectRows(rowRange[0], rowRange[1], comp, compVal, []int{})
+			if err != nil || !reflect.DeepEqual(rows, expected) {
+				t.Errorf("rows %v with comparison %v: expected %v, got %v (%v)", rowRange, comp, expected, rows, err)
+			}
+		}
+	}
+
+	if _, err := evaluator.SelectRows(0, numRows+1, EQ, compVal, nil); err == nil {
+		t.Error("selecting out of bounds rows succeeded")
+	}
+}
+
+func testDataStoreAddRowsGetRange(ds DataStore, values interface{}, t *testing.T) {
+	numRows := ds.GetNumRows()
+
+	if _, err := ds.AddRows(ds.GetDataType(), []bool{true}); err == nil {
+		t.Error("unexpected success when adding values of an unknown type")
+	}
+
+	firstIndex, err := ds.AddRows(ds.GetDataType(), values)
+	if err != nil || firstIndex != numRows {
+		t.Fatalf("unexpected result of AddRows: %d (%v)", firstIndex, err)
+	}
+
+	added, err := ds.GetRange(numRows, ds.GetNumRows())
+	if err != nil || !reflect.DeepEqual(added, values) {
+		t.Errorf("expected %#v, got %#v (%v)", values, added, err)
+	}
+
+	for row := 0; row < ds.GetNumRows(); row++ {
+		rangeValue, _ := ds.GetRange(row, row+1)
+		value, _ := ds.GetRow(row)
+		if !reflect.DeepEqual(reflect.ValueOf(rangeValue).Index(0).Interface(), value) {
+			t.Errorf("row %d: GetRange returned %#v, GetRow returned %#v", row, rangeValue, value)
+		}
+	}
+
+	if _, err := ds.GetRange(0, ds.GetNumRows()+1); err == nil {
+		t.Error("unexpected success when reading out of bounds rows")
+	}
+}
@@ -0,0 +1,149 @@
+package csgo
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// DeltaBlockSize is the number of rows between two checkpoints of a DeltaDataStore.
+const DeltaBlockSize = 128
+
+// DeltaDataStore is a DataStore for ungrouped INT data storing the differences between consecutive
+// values. It is meant for (mostly) increasing columns like keys or timestamps, whose differences
+// only need a few bits. Random access starts at the last checkpoint (the absolute value of every
+// DeltaBlockSize-th row).
+type DeltaDataStore struct {
+	// Checkpoints contains the value of the first row of every block.
+	Checkpoints []int
+	// Width is the number of bits per difference.
+	Width int
+	// Deltas contains the zigzag encoded differences of every row to its predecessor (0 for the
+	// first row of a block).
+	Deltas  BitStream
+	Last    int
+	NumRows int
+}
+
+// NewDeltaDataStore creates a new DeltaDataStore.
+func NewDeltaDataStore() DataStore {
+	return &DeltaDataStore{Checkpoints: []int{}}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *DeltaDataStore) GetDataType() DataTypes {
+	return INT
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *DeltaDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// appendValues adds the values to the column, repacking the differences at most once.
+func (ds *DeltaDataStore) appendValues(values []int) {
+	deltas := make([]uint64, len(values))
+	width := ds.Width
+	last := ds.Last
+	for i, value := range values {
+		if (ds.NumRows+i)%DeltaBlockSize != 0 {
+			deltas[i] = zigzag(value - last)
+			width = max(width, bits.Len64(deltas[i]))
+		}
+		last = value
+	}
+
+	if width > ds.Width {
+		ds.Deltas = ds.Deltas.repack(ds.NumRows, ds.Width, width)
+		ds.Width = width
+	}
+
+	for i, value := range values {
+		if ds.NumRows%DeltaBlockSize == 0 {
+			ds.Checkpoints = append(ds.Checkpoints, value)
+		}
+		ds.Deltas.Write(deltas[i], ds.Width)
+		ds.Last = value
+		ds.NumRows++
+	}
+}
+
+// AddRow adds a new row to the column.
+func (ds *DeltaDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValue, rightType := value.(int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	ds.appendValues([]int{intValue})
+	return ds.NumRows - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *DeltaDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= ds.NumRows {
+		return nil, errors.New("index out of bounds")
+	}
+
+	value := ds.Checkpoints[rowIndex/DeltaBlockSize]
+	for row := rowIndex - rowIndex%DeltaBlockSize + 1; row <= rowIndex; row++ {
+		value += unzigzag(ds.Deltas.Read(row*ds.Width, ds.Width))
+	}
+	return value, nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *DeltaDataStore) GetNumRows() int {
+	return ds.NumRows
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+func (ds *DeltaDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValues, rightType := values.([]int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := ds.NumRows
+	ds.appendValues(intValues)
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end). Only the first value is decoded starting at
+// its checkpoint, all further values are decoded sequentially.
+func (ds *DeltaDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, ds.NumRows); err != nil {
+		return nil, err
+	}
+
+	values := make([]int, 0, end-start)
+	if start == end {
+		return values, nil
+	}
+
+	first, _ := ds.GetRow(start)
+	value := first.(int)
+	values = append(values, value)
+
+	for row := start + 1; row < end; row++ {
+		if row%DeltaBlockSize == 0 {
+			value = ds.Checkpoints[row/DeltaBlockSize]
+		} else {
+			value += unzigzag(ds.Deltas.Read(row*ds.Width, ds.Width))
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *DeltaDataStore) MemoryUsage() int {
+	return sliceHeaderBytes + cap(ds.Checkpoints)*wordBytes + 3*wordBytes + ds.Deltas.memoryUsage()
+}
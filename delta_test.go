@@ -0,0 +1,58 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createDeltaDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewDeltaDataStore(), 1, 2, 3),
+		fillDataStore(NewDeltaDataStore(), 1000, -1000, 1<<40, -1<<40),
+		NewDeltaDataStore(),
+	}
+}
+
+func TestDeltaDataStoreAddRow(t *testing.T) {
+	for _, ds := range createDeltaDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestDeltaDataStoreGetRow(t *testing.T) {
+	for _, ds := range createDeltaDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestDeltaDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createDeltaDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestDeltaDataStoreAddRowsGetRange(t *testing.T) {
+	values := make([]int, 3*DeltaBlockSize+17)
+	for i := range values {
+		values[i] = 1230000000 + i*60 + i%7
+	}
+	values[DeltaBlockSize+3] = 5
+
+	for _, ds := range createDeltaDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	// increasing timestamps only need a few bits per value
+	ds := NewDeltaDataStore().(*DeltaDataStore)
+	ds.AddRows(INT, values[:DeltaBlockSize])
+	if ds.Width != 7 || len(ds.Checkpoints) != 1 {
+		t.Errorf("unexpected encoding: width %d, %d checkpoints", ds.Width, len(ds.Checkpoints))
+	}
+
+	for start := 0; start < DeltaBlockSize; start += 13 {
+		data, _ := ds.GetRange(start, DeltaBlockSize)
+		if !reflect.DeepEqual(data, values[start:DeltaBlockSize]) {
+			t.Errorf("range starting at %d: expected %v, got %v", start, values[start:DeltaBlockSize], data)
+		}
+	}
+}
@@ -1,33 +1,57 @@
 package csgo
 
-import "errors"
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
 
 // DictEncodedDataStore is a DataStore apllying dictionary encoding
 type DictEncodedDataStore struct {
-	DataType   DataTypes
-	Flags      ColumnFlags
-	Dictionary map[int]interface{}
+	DataType DataTypes
+	Flags    ColumnFlags
+	// Dictionary contains the distinct values, the code of a value is its row index. Ungrouped
+	// values are stored unboxed (see newValueStore).
+	Dictionary DataStore
 
 	Data DataStore
+
+	// IntCodes, FloatCodes and StringCodes map the values in Dictionary back to their codes. Only
+	// the map matching DataType is used. GroupCodes maps the hash of grouped values (see
+	// hashGroup) to the codes of all values with that hash.
+	IntCodes    map[int]int
+	FloatCodes  map[float64]int
+	StringCodes map[string]int
+	GroupCodes  map[uint64][]int
+
+	// Sorted is true if the order of the codes matches the order of the values (see SortDictionary).
+	Sorted bool
 }
 
 // NewDictEncodedDataStore creates a new DictEncodedDataStore.
 func NewDictEncodedDataStore(typ DataTypes, flags ColumnFlags, internalDataStoreType Compression) DataStore {
-	ds := &DictEncodedDataStore{DataType: typ, Flags: flags, Dictionary: map[int]interface{}{}}
-	switch internalDataStoreType {
-	case NOCOMP:
-		ds.Data = NewBasicDataStore(INT, 0)
-	case RLE:
-		ds.Data = NewRLEDataStore(INT, 0)
-	case DICT:
-		// prevent unnecessary looping
-		ds.Data = NewBasicDataStore(INT, 0)
-	default:
-		ds.Data = NewBasicDataStore(INT, 0)
-	}
+	ds := &DictEncodedDataStore{DataType: typ, Flags: flags, Dictionary: newValueStore(typ, flags), Sorted: flags == 0}
+	ds.buildCodes()
+	// another DICT layer would only add unnecessary looping
+	ds.Data = newIntDataStore(internalDataStoreType)
 	return ds
 }
 
+// codeEncoding returns the encoding of the internal DataStore of a dictionary.
+func codeEncoding(data DataStore) Compression {
+	switch data.(type) {
+	case *RLEDataStore:
+		return RLE
+	case *FORDataStore:
+		return FOR
+	case *DeltaDataStore:
+		return DELTA
+	case *BitPackedDataStore:
+		return BITPACK
+	}
+	return NOCOMP
+}
+
 // GetDataType returns the type of the stored data.
 func (ds *DictEncodedDataStore) GetDataType() DataTypes {
 	return ds.DataType
@@ -72,25 +96,169 @@ func (ds *DictEncodedDataStore) AddRow(typ DataTypes, value interface{}) (int, e
 		return -1, errors.New("type mismatch")
 	}
 
-	// looking up for matching value in the Hashtable
-	index := -1
-	for k, v := range ds.Dictionary {
-		if v == value { // if found
-			index = k // hold on the key into index-variable
-			break     // and break the look-up-loop
+	// add the index of the value to the column (the index of the value not the value)
+	ds.Data.AddRow(INT, ds.encode(value)) // = append(Data, index)
+	return ds.Data.GetNumRows() - 1, nil  //len(Data) - 1, nil
+}
+
+// encode returns the key of value in the dictionary. Values not yet contained get added.
+func (ds *DictEncodedDataStore) encode(value interface{}) int {
+	// the reverse maps are only written while adding rows, so concurrent lookups stay safe
+	if !ds.hasCodes() {
+		ds.buildCodes()
+	}
+
+	if code, found := ds.Lookup(value); found {
+		return code
+	}
+
+	if ds.Dictionary == nil {
+		ds.Dictionary = newValueStore(ds.DataType, ds.Flags)
+	}
+
+	// generate a new key sequentially
+	code := ds.numCodes()
+	if ds.Sorted && (isNaN(value) || code > 0 && !compFuncs[ds.DataType][LT](ds.value(code-1), value)) {
+		ds.Sorted = false
+	}
+	ds.Dictionary.AddRow(ds.DataType, value)
+	ds.addCode(value, code)
+	return code
+}
+
+// numCodes returns the number of values in the dictionary.
+func (ds *DictEncodedDataStore) numCodes() int {
+	if ds.Dictionary == nil {
+		return 0
+	}
+	return ds.Dictionary.GetNumRows()
+}
+
+// value returns the dictionary value of code.
+func (ds *DictEncodedDataStore) value(code int) interface{} {
+	return valueAt(ds.Dictionary, code)
+}
+
+// Lookup returns the code of value in the dictionary, if it is contained.
+// DataStores without reverse maps (e.g. created by a struct literal) are searched linearly until
+// the next row gets added.
+func (ds *DictEncodedDataStore) Lookup(value interface{}) (int, bool) {
+	if !ds.hasCodes() {
+		for code := 0; code < ds.numCodes(); code++ {
+			if sameValue(ds.value(code), value) {
+				return code, true
+			}
 		}
+		return -1, false
 	}
 
-	if index == -1 { // in the case, no matching value found. That means index has not been changed
-		index = len(ds.Dictionary) // so generate a new key sequentially (keys-number + 1)
-		// 1. case (Hashtable is empty) => key = keys-number (= 0) + 1 = 1
-		// 2. case (Hashtable is not empty) => key = keys-number + 1
-		ds.Dictionary[index] = value // add the (key, value) into the Hashtable
+	if ds.Flags != 0 {
+		for _, code := range ds.GroupCodes[hashGroup(value)] {
+			if sameValue(ds.value(code), value) {
+				return code, true
+			}
+		}
+		return -1, false
 	}
 
-	// add the index of the value to the column (the index of the value not the value)
-	ds.Data.AddRow(INT, index)           // = append(Data, index)
-	return ds.Data.GetNumRows() - 1, nil //len(Data) - 1, nil
+	code, found := -1, false
+	switch typed := value.(type) {
+	case int:
+		code, found = ds.IntCodes[typed]
+	case float64:
+		code, found = ds.FloatCodes[typed]
+	case string:
+		code, found = ds.StringCodes[typed]
+	}
+	return code, found
+}
+
+// sameValue returns whether two dictionary values are equal. Grouped values are compared
+// element by element.
+func sameValue(a interface{}, b interface{}) bool {
+	switch a.(type) {
+	case []int, []float64, []string:
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// hashGroup returns a 64 bit hash for a grouped value.
+func hashGroup(value interface{}) uint64 {
+	hash := uint64(0)
+	switch group := value.(type) {
+	case []int:
+		for _, element := range group {
+			hash = hash*31 + hashInt(element)
+		}
+	case []float64:
+		for _, element := range group {
+			hash = hash*31 + hashFloat(element)
+		}
+	case []string:
+		for _, element := range group {
+			hash = hash*31 + hashString(element)
+		}
+	}
+	return hash
+}
+
+// hasCodes returns whether the reverse map matching the data type and flags exists.
+func (ds *DictEncodedDataStore) hasCodes() bool {
+	switch {
+	case ds.Flags != 0:
+		return ds.GroupCodes != nil
+	case ds.DataType == INT:
+		return ds.IntCodes != nil
+	case ds.DataType == FLOAT:
+		return ds.FloatCodes != nil
+	case ds.DataType == STRING:
+		return ds.StringCodes != nil
+	}
+	return false
+}
+
+// buildCodes creates the reverse map matching the data type and flags and fills it with the
+// current dictionary (e.g. for DataStores not created by NewDictEncodedDataStore).
+func (ds *DictEncodedDataStore) buildCodes() {
+	switch {
+	case ds.Flags != 0:
+		ds.GroupCodes = map[uint64][]int{}
+	case ds.DataType == INT:
+		ds.IntCodes = map[int]int{}
+	case ds.DataType == FLOAT:
+		ds.FloatCodes = map[float64]int{}
+	case ds.DataType == STRING:
+		ds.StringCodes = map[string]int{}
+	}
+
+	for code := 0; code < ds.numCodes(); code++ {
+		ds.addCode(ds.value(code), code)
+	}
+}
+
+// addCode adds a mapping from value to code to the reverse map matching the data type.
+func (ds *DictEncodedDataStore) addCode(value interface{}, code int) {
+	if ds.Flags != 0 {
+		hash := hashGroup(value)
+		ds.GroupCodes[hash] = append(ds.GroupCodes[hash], code)
+		return
+	}
+
+	switch typed := value.(type) {
+	case int:
+		if ds.IntCodes != nil {
+			ds.IntCodes[typed] = code
+		}
+	case float64:
+		if ds.FloatCodes != nil {
+			ds.FloatCodes[typed] = code
+		}
+	case string:
+		if ds.StringCodes != nil {
+			ds.StringCodes[typed] = code
+		}
+	}
 }
 
 // GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
@@ -100,7 +268,7 @@ func (ds *DictEncodedDataStore) GetRow(rowIndex int) (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		return ds.Dictionary[key.(int)], nil
+		return ds.value(key.(int)), nil
 	}
 	return nil, errors.New("out of column's range")
 }
@@ -109,3 +277,256 @@ func (ds *DictEncodedDataStore) GetRow(rowIndex int) (interface{}, error) {
 func (ds *DictEncodedDataStore) GetNumRows() int {
 	return ds.Data.GetNumRows() //len(ds.Data)
 }
+
+// SelectRows appends the indices of all rows in [start, end) whose value satisfies the predicate
+// (value comp compVal) to out. The predicate is evaluated only once per dictionary entry, the rows
+// are then filtered by their codes.
+func (ds *DictEncodedDataStore) SelectRows(start int, end int, comp Comparison, compVal interface{}, out []int) ([]int, error) {
+	compFunc, err := checkPredicate(ds.DataType, ds.Flags, comp)
+	if err != nil {
+		return out, err
+	}
+
+	// matches reports whether the value of a code satisfies the predicate
+	var matches func(code int) bool
+	anyMatch := false
+
+	switch {
+	case comp == EQ:
+		// the only matching code can be looked up directly
+		matchingCode, found := ds.Lookup(compVal)
+		matches = func(code int) bool { return code == matchingCode }
+		anyMatch = found
+	case ds.Sorted && comp != NEQ:
+		// the matching codes of an order preserving dictionary form a single range
+		low, high := ds.codeRange(comp, compVal)
+		matches = func(code int) bool { return code >= low && code < high }
+		anyMatch = low < high
+	default:
+		matchingCodes := make([]bool, ds.numCodes())
+		for code := range matchingCodes {
+			if compFunc(ds.value(code), compVal) {
+				matchingCodes[code] = true
+				anyMatch = true
+			}
+		}
+		matches = func(code int) bool { return matchingCodes[code] }
+	}
+
+	if !anyMatch {
+		if start < 0 || end > ds.GetNumRows() || start > end {
+			return out, errors.New("out of column's range")
+		}
+		return out, nil
+	}
+
+	err = scanRuns(ds.Data, start, end, func(runStart int, runEnd int, code interface{}) {
+		if matches(code.(int)) {
+			for row := runStart; row < runEnd; row++ {
+				out = append(out, row)
+			}
+		}
+	})
+	return out, err
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+// The codes of all values are appended to the internal DataStore at once.
+func (ds *DictEncodedDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("invalid type")
+	}
+
+	boxed, err := boxValues(ds.DataType, ds.Flags, values)
+	if err != nil {
+		return -1, err
+	}
+
+	codes := make([]int, len(boxed))
+	for i, value := range boxed {
+		codes[i] = ds.encode(value)
+	}
+
+	return ds.Data.AddRows(INT, codes)
+}
+
+// GetRange returns the values of the rows [start, end). The codes of the whole range are read at
+// once and then decoded.
+func (ds *DictEncodedDataStore) GetRange(start int, end int) (interface{}, error) {
+	codes, err := ds.Data.GetRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Dictionary == nil {
+		return unboxValues(ds.DataType, ds.Flags, []interface{}{})
+	}
+	return gatherValues(ds.Dictionary, ds.DataType, ds.Flags, codes.([]int))
+}
+
+// codeRange returns the range [low, high) of all codes whose values satisfy the range predicate
+// (value comp compVal). The dictionary has to be sorted.
+func (ds *DictEncodedDataStore) codeRange(comp Comparison, compVal interface{}) (int, int) {
+	less := compFuncs[ds.DataType][LT]
+	numCodes := ds.numCodes()
+
+	// first code with a value >= compVal and first code with a value > compVal
+	lower := sort.Search(numCodes, func(code int) bool { return !less(ds.value(code), compVal) })
+	upper := sort.Search(numCodes, func(code int) bool { return less(compVal, ds.value(code)) })
+
+	switch comp {
+	case LT:
+		return 0, lower
+	case LEQ:
+		return 0, upper
+	case GT:
+		return upper, numCodes
+	case GEQ:
+		return lower, numCodes
+	}
+	return 0, 0
+}
+
+// SortDictionary reassigns the codes, so their order matches the order of the values (order
+// preserving dictionary). Afterwards range predicates and sorting can be evaluated on the codes.
+// Values added later keep the dictionary sorted as long as they are added in ascending order.
+// Dictionaries containing NaN can't be ordered and are left unsorted.
+func (ds *DictEncodedDataStore) SortDictionary() error {
+	if ds.Flags != 0 {
+		return errors.New("grouped values can not be sorted")
+	}
+	if ds.Sorted {
+		return nil
+	}
+	for code := 0; code < ds.numCodes(); code++ {
+		if isNaN(ds.value(code)) {
+			return errors.New("dictionaries containing NaN can not be sorted")
+		}
+	}
+
+	less := compFuncs[ds.DataType][LT]
+	order := make([]int, ds.numCodes())
+	for code := range order {
+		order[code] = code
+	}
+	sort.Slice(order, func(a int, b int) bool { return less(ds.value(order[a]), ds.value(order[b])) })
+
+	newCodes := make([]int, len(order))
+	for newCode, oldCode := range order {
+		newCodes[oldCode] = newCode
+	}
+	values, err := gatherValues(ds.Dictionary, ds.DataType, ds.Flags, order)
+	if err != nil {
+		return err
+	}
+	dictionary := newValueStore(ds.DataType, ds.Flags)
+	if _, err := dictionary.AddRows(ds.DataType, values); err != nil {
+		return err
+	}
+
+	codes, err := ds.Data.GetRange(0, ds.Data.GetNumRows())
+	if err != nil {
+		return err
+	}
+	for row, code := range codes.([]int) {
+		codes.([]int)[row] = newCodes[code]
+	}
+
+	data := newIntDataStore(codeEncoding(ds.Data))
+	if _, err := data.AddRows(INT, codes); err != nil {
+		return err
+	}
+
+	ds.Dictionary = dictionary
+	ds.Data = data
+	ds.buildCodes()
+	ds.Sorted = true
+	return nil
+}
+
+// orderedCodes returns the order preserving dictionary backing col (directly or through a position
+// list) and the codes of all rows of col. If col isn't backed by a sorted dictionary, nil is
+// returned.
+func orderedCodes(col *Column) (*DictEncodedDataStore, []int) {
+	data := col.Data.(DataStore)
+	var positions []int
+	if view, isView := data.(*PositionListDataStore); isView {
+		data = view.Source
+		positions = view.Positions
+	}
+
+	ds, isDict := data.(*DictEncodedDataStore)
+	if !isDict || !ds.Sorted {
+		return nil, nil
+	}
+
+	codes, err := ds.Data.GetRange(0, ds.Data.GetNumRows())
+	if err != nil {
+		return nil, nil
+	}
+
+	if positions == nil {
+		return ds, codes.([]int)
+	}
+
+	rowCodes := make([]int, len(positions))
+	for row, position := range positions {
+		rowCodes[row] = codes.([]int)[position]
+	}
+	return ds, rowCodes
+}
+
+// joinKeys translates the codes of two order preserving dictionaries into keys sharing the same
+// order, i.e. the keys of equal values are equal and the keys of lesser values are lesser.
+// Values of the left dictionary get the key 2*code, values of the right dictionary not contained in
+// the left dictionary get the odd key in between their neighbours.
+func joinKeys(left *DictEncodedDataStore, leftCodes []int, right *DictEncodedDataStore, rightCodes []int) ([]int, []int) {
+	if left == right {
+		return leftCodes, rightCodes
+	}
+
+	less := compFuncs[left.DataType][LT]
+	rightCodeKeys := make([]int, right.numCodes())
+	leftCode := 0
+	for rightCode := range rightCodeKeys {
+		value := right.value(rightCode)
+		for leftCode < left.numCodes() && less(left.value(leftCode), value) {
+			leftCode++
+		}
+
+		if leftCode < left.numCodes() && !less(value, left.value(leftCode)) {
+			rightCodeKeys[rightCode] = 2 * leftCode
+		} else {
+			rightCodeKeys[rightCode] = 2*leftCode - 1
+		}
+	}
+
+	leftKeys := make([]int, len(leftCodes))
+	for row, code := range leftCodes {
+		leftKeys[row] = 2 * code
+	}
+
+	rightKeys := make([]int, len(rightCodes))
+	for row, code := range rightCodes {
+		rightKeys[row] = rightCodeKeys[code]
+	}
+	return leftKeys, rightKeys
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore, including the
+// dictionary, the reverse map and the codes.
+func (ds *DictEncodedDataStore) MemoryUsage() int {
+	// DataType, Flags, Sorted, the four map pointers and the dictionary and internal DataStores
+	size := 7*wordBytes + 2*interfaceBytes + ds.Data.MemoryUsage()
+	if ds.Dictionary != nil {
+		size += ds.Dictionary.MemoryUsage()
+	}
+
+	// the reverse map shares the string data with the dictionary
+	size += len(ds.IntCodes) * (2*wordBytes + mapEntryBytes)
+	size += len(ds.FloatCodes) * (2*wordBytes + mapEntryBytes)
+	size += len(ds.StringCodes) * (stringHeaderBytes + wordBytes + mapEntryBytes)
+	for _, codes := range ds.GroupCodes {
+		size += wordBytes + sliceHeaderBytes + mapEntryBytes + cap(codes)*wordBytes
+	}
+	return size
+}
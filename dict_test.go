@@ -1,6 +1,10 @@
 package csgo
 
-import "testing"
+import (
+	"math"
+	"reflect"
+	"testing"
+)
 
 func fillDataStore(ds DataStore, data ...interface{}) DataStore {
 	for _, value := range data {
@@ -11,12 +15,12 @@ func fillDataStore(ds DataStore, data ...interface{}) DataStore {
 
 func createDictEncodedDataStoreCases() []DictEncodedDataStore {
 	return []DictEncodedDataStore{
-		DictEncodedDataStore{INT, 0, map[int]interface{}{0: 215, 1: 9e+14}, fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
-		DictEncodedDataStore{FLOAT, 0, map[int]interface{}{0: 215.0e+20, 1: -9000e+14}, fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
-		DictEncodedDataStore{STRING, 0, map[int]interface{}{0: "Max-Planck-Ring, Ilmenau", 1: "Mazeh, Damascus, Syria"}, fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
-		DictEncodedDataStore{INT, 0, map[int]interface{}{0: 215, 1: 9e+14}, fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
-		DictEncodedDataStore{FLOAT, 0, map[int]interface{}{0: 215.0e+20, 1: -9000e+14}, fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
-		DictEncodedDataStore{STRING, 0, map[int]interface{}{0: "Max-Planck-Ring, Ilmenau", 1: "Mazeh, Damascus, Syria"}, fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: INT, Dictionary: fillDataStore(NewTypedDataStore(INT), 215, int(9e+14)), Data: fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: FLOAT, Dictionary: fillDataStore(NewTypedDataStore(FLOAT), 215.0e+20, -9000e+14), Data: fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: STRING, Dictionary: fillDataStore(NewTypedDataStore(STRING), "Max-Planck-Ring, Ilmenau", "Mazeh, Damascus, Syria"), Data: fillDataStore(NewBasicDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: INT, Dictionary: fillDataStore(NewTypedDataStore(INT), 215, int(9e+14)), Data: fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: FLOAT, Dictionary: fillDataStore(NewTypedDataStore(FLOAT), 215.0e+20, -9000e+14), Data: fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
+		DictEncodedDataStore{DataType: STRING, Dictionary: fillDataStore(NewTypedDataStore(STRING), "Max-Planck-Ring, Ilmenau", "Mazeh, Damascus, Syria"), Data: fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 1, 0, 0, 0, 1)},
 		//{INT, {0: 215, 1: 9e+14}, {int(1), int(1), int(1), int(0), int(0), int(0), int(1)}},
 		//{FLOAT, {0: 215.0e+20, 1: -9000e+14}, int(1), int(1), int(1), int(0), int(0), int(0), int(1)}},
 		//{STRING, {0: "Max-Planck-Ring, Ilmenau", 1: "Mazeh, Damascus, Syria"}, int(1), int(1), int(1), int(0), int(0), int(0), int(1)}},
@@ -46,3 +50,145 @@ func TestDictEncodedDataStoreGetDataType(t *testing.T) {
 		testDataStoreGetDataType(&ds, ds.DataType, t)
 	}
 }
+
+func TestDictEncodedDataStoreSelectRows(t *testing.T) {
+	for _, internal := range []Compression{NOCOMP, RLE} {
+		testDataStoreSelectRows(fillDataStore(NewDictEncodedDataStore(INT, 0, internal), 3, 3, 1, 2, 2, 2, 5, 3), 2, t)
+		testDataStoreSelectRows(fillDataStore(NewDictEncodedDataStore(FLOAT, 0, internal), 0.5, 1.5, 1.5, 1.5, 0.5, 0.5), 1.5, t)
+		testDataStoreSelectRows(fillDataStore(NewDictEncodedDataStore(STRING, 0, internal), "b", "b", "a", "c", "c", "a"), "x", t)
+	}
+}
+
+func TestDictEncodedDataStoreAddRowsGetRange(t *testing.T) {
+	for _, internal := range []Compression{NOCOMP, RLE} {
+		testDataStoreAddRowsGetRange(NewDictEncodedDataStore(INT, 0, internal), []int{3, 3, 1, 3, 2}, t)
+		testDataStoreAddRowsGetRange(NewDictEncodedDataStore(FLOAT, 0, internal), []float64{0.5, 1.5, 0.5}, t)
+		testDataStoreAddRowsGetRange(NewDictEncodedDataStore(STRING, 0, internal), []string{"a", "b", "b"}, t)
+	}
+}
+
+func TestDictEncodedDataStoreLookup(t *testing.T) {
+	ds := fillDataStore(NewDictEncodedDataStore(STRING, 0, NOCOMP), "b", "a", "b", "c").(*DictEncodedDataStore)
+
+	if ds.Dictionary.GetNumRows() != 3 || len(ds.StringCodes) != 3 {
+		t.Errorf("expected 3 dictionary entries, got %v", ds.Dictionary)
+	}
+
+	// ungrouped values are stored unboxed
+	for code, value := range ds.Dictionary.(*TypedDataStore[string]).Values {
+		if lookupCode, found := ds.Lookup(value); !found || lookupCode != code {
+			t.Errorf("lookup of %v returned %d (%v), expected %d", value, lookupCode, found, code)
+		}
+	}
+
+	if _, found := ds.Lookup("d"); found {
+		t.Error("lookup of a missing value succeeded")
+	}
+	if _, found := ds.Lookup(1); found {
+		t.Error("lookup of a value of the wrong type succeeded")
+	}
+
+	// DataStores created without reverse map can be read concurrently, adding a row builds it
+	literal := createDictEncodedDataStoreCases()[2]
+	parallelFor(4, 4, func(task int) {
+		if code, found := literal.Lookup("Mazeh, Damascus, Syria"); !found || code != 1 {
+			t.Errorf("lookup in a dictionary literal returned %d (%v)", code, found)
+		}
+	})
+	if literal.StringCodes != nil {
+		t.Error("lookup modified the dictionary")
+	}
+	if literal.AddRow(STRING, "Mazeh, Damascus, Syria"); len(literal.StringCodes) != 2 || literal.Dictionary.GetNumRows() != 2 {
+		t.Errorf("adding a known value changed the dictionary to %v", literal.Dictionary)
+	}
+}
+
+func TestDictEncodedDataStoreGrouped(t *testing.T) {
+	ds := NewDictEncodedDataStore(INT, GROUPED, NOCOMP).(*DictEncodedDataStore)
+	groups := [][]int{{1, 2}, {3}, {1, 2}, {}, {3}, {2, 1}}
+
+	for _, group := range groups {
+		if _, err := ds.AddRow(INT, group); err != nil {
+			t.Errorf("adding %v failed: %v", group, err)
+		}
+	}
+
+	if ds.Dictionary.GetNumRows() != 4 {
+		t.Errorf("expected 4 dictionary entries, got %v", ds.Dictionary)
+	}
+	if code, found := ds.Lookup([]int{3}); !found || !reflect.DeepEqual(ds.value(code), []int{3}) {
+		t.Errorf("lookup of a group returned %d (%v)", code, found)
+	}
+	if _, found := ds.Lookup([]int{4}); found {
+		t.Error("lookup of a missing group succeeded")
+	}
+
+	data, _ := ds.GetRange(0, ds.GetNumRows())
+	if !reflect.DeepEqual(data, groups) {
+		t.Errorf("expected %v, got %v", groups, data)
+	}
+}
+
+func TestDictEncodedDataStoreSortDictionary(t *testing.T) {
+	values := []string{"d", "b", "d", "a", "c", "b"}
+
+	for _, internal := range []Compression{NOCOMP, RLE} {
+		ds := NewDictEncodedDataStore(STRING, 0, internal).(*DictEncodedDataStore)
+		ds.AddRows(STRING, values)
+
+		if ds.Sorted {
+			t.Error("dictionary filled in unsorted order is marked as sorted")
+		}
+
+		if err := ds.SortDictionary(); err != nil || !ds.Sorted {
+			t.Fatalf("sorting the dictionary failed: %v", err)
+		}
+
+		data, _ := ds.GetRange(0, ds.GetNumRows())
+		if !reflect.DeepEqual(data, values) {
+			t.Errorf("expected %v after sorting, got %v", values, data)
+		}
+
+		for code := 1; code < ds.numCodes(); code++ {
+			if ds.value(code-1).(string) >= ds.value(code).(string) {
+				t.Errorf("dictionary %v is not sorted", ds.Dictionary)
+			}
+		}
+
+		testDataStoreSelectRows(ds, "b", t)
+		testDataStoreSelectRows(ds, "bb", t)
+
+		// appending in ascending order keeps the dictionary sorted
+		ds.AddRow(STRING, "e")
+		ds.AddRow(STRING, "a")
+		if !ds.Sorted {
+			t.Error("appending values in ascending order unsorted the dictionary")
+		}
+		ds.AddRow(STRING, "ab")
+		if ds.Sorted {
+			t.Error("appending a value out of order kept the dictionary sorted")
+		}
+	}
+}
+
+func TestDictEncodedDataStoreSortDictionaryNaN(t *testing.T) {
+	nan := math.NaN()
+
+	ds := NewDictEncodedDataStore(FLOAT, 0, NOCOMP).(*DictEncodedDataStore)
+	ds.AddRows(FLOAT, []float64{1, nan, 0.5, 2, 3, nan, 0.1})
+	if err := ds.SortDictionary(); err == nil || ds.Sorted {
+		t.Error("dictionary containing NaN was sorted")
+	}
+	for _, compVal := range []float64{0.5, 1.5, nan} {
+		testDataStoreSelectRows(ds, compVal, t)
+	}
+
+	// NaN as the first value doesn't keep the dictionary sorted either
+	ds = NewDictEncodedDataStore(FLOAT, 0, NOCOMP).(*DictEncodedDataStore)
+	ds.AddRow(FLOAT, nan)
+	if ds.Sorted {
+		t.Error("dictionary containing NaN is marked as sorted")
+	}
+	ds.AddRows(FLOAT, []float64{1, 2, nan})
+	testDataStoreSelectRows(ds, 1.0, t)
+}
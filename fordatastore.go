@@ -0,0 +1,182 @@
+package csgo
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// FORBlockSize is the number of values per block of a FORDataStore.
+const FORBlockSize = 1024
+
+// FORBlock is a block of a frame of reference encoded column.
+type FORBlock struct {
+	// Reference is the first value of the block, all values are stored relative to it.
+	Reference int
+	// Width is the number of bytes per offset (1, 2, 4 or 8).
+	Width int
+	// Offsets contains the zigzag encoded differences of all values to Reference (little endian,
+	// Width bytes each).
+	Offsets []byte
+}
+
+// FORDataStore is a frame of reference encoded DataStore for ungrouped INT data. Values close to
+// the first value of their block only need 1 or 2 bytes.
+type FORDataStore struct {
+	Blocks  []FORBlock
+	NumRows int
+}
+
+// NewFORDataStore creates a new FORDataStore.
+func NewFORDataStore() DataStore {
+	return &FORDataStore{Blocks: []FORBlock{}}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *FORDataStore) GetDataType() DataTypes {
+	return INT
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *FORDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// zigzag maps signed offsets to unsigned ones, so offsets of small magnitude stay small.
+func zigzag(value int) uint64 {
+	return uint64((value << 1) ^ (value >> 63))
+}
+
+// unzigzag reverses zigzag.
+func unzigzag(value uint64) int {
+	return int(value>>1) ^ -int(value&1)
+}
+
+// offsetWidth returns the number of bytes needed to store offset.
+func offsetWidth(offset uint64) int {
+	switch {
+	case offset <= 0xff:
+		return 1
+	case offset <= 0xffff:
+		return 2
+	case offset <= 0xffffffff:
+		return 4
+	}
+	return 8
+}
+
+// get returns the offset at index.
+func (block *FORBlock) get(index int) uint64 {
+	data := block.Offsets[index*block.Width:]
+	switch block.Width {
+	case 1:
+		return uint64(data[0])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(data))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(data))
+	}
+	return binary.LittleEndian.Uint64(data)
+}
+
+// append adds offset to the block, widening all offsets of the block if necessary.
+func (block *FORBlock) append(offset uint64) {
+	if width := offsetWidth(offset); width > block.Width {
+		numOffsets := len(block.Offsets) / block.Width
+		widened := FORBlock{Reference: block.Reference, Width: width}
+		for i := 0; i < numOffsets; i++ {
+			widened.append(block.get(i))
+		}
+		*block = widened
+	}
+
+	switch block.Width {
+	case 1:
+		block.Offsets = append(block.Offsets, byte(offset))
+	case 2:
+		block.Offsets = binary.LittleEndian.AppendUint16(block.Offsets, uint16(offset))
+	case 4:
+		block.Offsets = binary.LittleEndian.AppendUint32(block.Offsets, uint32(offset))
+	default:
+		block.Offsets = binary.LittleEndian.AppendUint64(block.Offsets, offset)
+	}
+}
+
+// AddRow adds a new row to the column.
+func (ds *FORDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValue, rightType := value.(int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	if ds.NumRows%FORBlockSize == 0 {
+		ds.Blocks = append(ds.Blocks, FORBlock{Reference: intValue, Width: 1})
+	}
+
+	block := &ds.Blocks[len(ds.Blocks)-1]
+	block.append(zigzag(intValue - block.Reference))
+	ds.NumRows++
+	return ds.NumRows - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *FORDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= ds.NumRows {
+		return nil, errors.New("index out of bounds")
+	}
+
+	block := &ds.Blocks[rowIndex/FORBlockSize]
+	return block.Reference + unzigzag(block.get(rowIndex%FORBlockSize)), nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *FORDataStore) GetNumRows() int {
+	return ds.NumRows
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+func (ds *FORDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != INT {
+		return -1, errors.New("invalid type")
+	}
+
+	intValues, rightType := values.([]int)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := ds.NumRows
+	for _, value := range intValues {
+		ds.AddRow(INT, value)
+	}
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end). The values are decoded block by block.
+func (ds *FORDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, ds.NumRows); err != nil {
+		return nil, err
+	}
+
+	values := make([]int, 0, end-start)
+	for row := start; row < end; {
+		block := &ds.Blocks[row/FORBlockSize]
+		blockEnd := min(end, (row/FORBlockSize+1)*FORBlockSize)
+		for ; row < blockEnd; row++ {
+			values = append(values, block.Reference+unzigzag(block.get(row%FORBlockSize)))
+		}
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *FORDataStore) MemoryUsage() int {
+	size := sliceHeaderBytes + wordBytes
+	for _, block := range ds.Blocks {
+		size += 2*wordBytes + sliceHeaderBytes + cap(block.Offsets)
+	}
+	return size + (cap(ds.Blocks)-len(ds.Blocks))*(2*wordBytes+sliceHeaderBytes)
+}
@@ -0,0 +1,49 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createFORDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewFORDataStore(), 1, 2, 3),
+		fillDataStore(NewFORDataStore(), 1000, -1000, 1<<40, -1<<62),
+		NewFORDataStore(),
+	}
+}
+
+func TestFORDataStoreAddRow(t *testing.T) {
+	for _, ds := range createFORDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestFORDataStoreGetRow(t *testing.T) {
+	for _, ds := range createFORDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestFORDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createFORDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestFORDataStoreAddRowsGetRange(t *testing.T) {
+	values := make([]int, 2*FORBlockSize+10)
+	for i := range values {
+		values[i] = 500 + (i*37)%300 - i%2*1000
+	}
+	values[FORBlockSize+5] = -1 << 50
+
+	for _, ds := range createFORDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	ds := fillDataStore(NewFORDataStore(), 10, 12, 9, 300).(*FORDataStore)
+	if !reflect.DeepEqual(ds.Blocks, []FORBlock{{Reference: 10, Width: 2, Offsets: []byte{0, 0, 4, 0, 1, 0, 0x44, 0x02}}}) {
+		t.Errorf("unexpected blocks %v", ds.Blocks)
+	}
+}
@@ -0,0 +1,182 @@
+package csgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ForeignKey declares that the values of the columns Columns of the relation Relation reference
+// the primary key (see PRIMARYKEY) of the relation Referenced.
+type ForeignKey struct {
+	Relation string
+	// Columns contains the names of the referencing columns in order of the primary key columns.
+	Columns    []string
+	Referenced string
+}
+
+// String returns a readable description of the foreign key (e.g. "PARTSUPP(PARTKEY) -> PART").
+func (fk ForeignKey) String() string {
+	return fmt.Sprintf("%s(%s) -> %s", fk.Relation, strings.Join(fk.Columns, ", "), fk.Referenced)
+}
+
+// OrphanReport is the result of validating a foreign key.
+type OrphanReport struct {
+	ForeignKey ForeignKey
+	// NumRows is the number of checked (referencing) rows.
+	NumRows int
+	// Rows contains the indices of all rows referencing a non-existing key.
+	Rows []int
+}
+
+// String returns a summary of the report, listing the first few orphaned rows.
+func (report OrphanReport) String() string {
+	const maxListedRows = 10
+
+	desc := fmt.Sprintf("%v: %d of %d rows orphaned", report.ForeignKey, len(report.Rows), report.NumRows)
+	if len(report.Rows) > 0 {
+		rows := []string{}
+		for _, row := range report.Rows {
+			if len(rows) == maxListedRows {
+				rows = append(rows, "...")
+				break
+			}
+			rows = append(rows, fmt.Sprint(row))
+		}
+		desc += " (rows " + strings.Join(rows, ", ") + ")"
+	}
+	return desc
+}
+
+// columnByName returns the column of r with the given name or nil.
+func columnByName(r Relation, name string) *Column {
+	for colIndex := range r.Columns {
+		if r.Columns[colIndex].Signature.Name == name {
+			return &r.Columns[colIndex]
+		}
+	}
+	return nil
+}
+
+// foreignKeyColumns returns the referencing columns and the referenced primary key columns of fk.
+func (c ColumnStore) foreignKeyColumns(fk ForeignKey) (cols []*Column, keyCols []*Column, err error) {
+	r, isRelation := c.GetRelation(fk.Relation).(Relation)
+	if !isRelation {
+		return nil, nil, fmt.Errorf("relation %s not found", fk.Relation)
+	}
+	referenced, isRelation := c.GetRelation(fk.Referenced).(Relation)
+	if !isRelation {
+		return nil, nil, fmt.Errorf("relation %s not found", fk.Referenced)
+	}
+
+	keyCols = referenced.keyColumns()
+	if len(keyCols) == 0 {
+		return nil, nil, fmt.Errorf("relation %s has no primary key", fk.Referenced)
+	}
+	if len(fk.Columns) != len(keyCols) {
+		return nil, nil, fmt.Errorf("primary key of %s has %d columns, got %d", fk.Referenced, len(keyCols), len(fk.Columns))
+	}
+
+	for keyIndex, name := range fk.Columns {
+		col := columnByName(r, name)
+		if col == nil {
+			return nil, nil, fmt.Errorf("column %s not found in relation %s", name, fk.Relation)
+		}
+		if col.Signature.Type != keyCols[keyIndex].Signature.Type || col.Signature.Flags&GROUPED != 0 {
+			return nil, nil, fmt.Errorf("column %s does not match the key column %s", name, keyCols[keyIndex].Signature.Name)
+		}
+		cols = append(cols, col)
+	}
+	return cols, keyCols, nil
+}
+
+// DeclareForeignKey adds the foreign key fk to the catalog of the column store. Both relations
+// have to exist already and the referencing columns have to match the primary key of the
+// referenced relation.
+func (c *ColumnStore) DeclareForeignKey(fk ForeignKey) error {
+	if _, _, err := c.foreignKeyColumns(fk); err != nil {
+		return err
+	}
+
+	for _, declared := range c.ForeignKeys {
+		if declared.String() == fk.String() {
+			return errors.New("foreign key already declared")
+		}
+	}
+
+	c.ForeignKeys = append(c.ForeignKeys, fk)
+	return nil
+}
+
+// validateForeignKey returns the rows of the referencing relation of fk whose key doesn't exist in
+// the referenced relation. Keys are looked up via the hash index of the primary key.
+func (c ColumnStore) validateForeignKey(fk ForeignKey) (OrphanReport, error) {
+	report := OrphanReport{ForeignKey: fk, Rows: []int{}}
+
+	cols, keyCols, err := c.foreignKeyColumns(fk)
+	if err != nil {
+		return report, err
+	}
+
+	values := make([][]interface{}, len(cols))
+	for keyIndex, col := range cols {
+		if values[keyIndex], err = boxValues(col.Signature.Type, 0, col.GetRawData()); err != nil {
+			return report, err
+		}
+	}
+
+	report.NumRows = cols[0].GetNumRows()
+	key := make([]interface{}, len(cols))
+	for row := 0; row < report.NumRows; row++ {
+		for keyIndex := range key {
+			key[keyIndex] = values[keyIndex][row]
+		}
+
+		keyRow, err := findKey(keyCols, key)
+		if err != nil {
+			return report, err
+		}
+		if keyRow < 0 {
+			report.Rows = append(report.Rows, row)
+		}
+	}
+	return report, nil
+}
+
+// ValidateForeignKeys checks all declared foreign keys and returns one report per foreign key.
+func (c ColumnStore) ValidateForeignKeys() ([]OrphanReport, error) {
+	reports := []OrphanReport{}
+	for _, fk := range c.ForeignKeys {
+		report, err := c.validateForeignKey(fk)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Load loads a CSV file into the relation relName (see Relation.Load) and validates the foreign
+// keys declared for that relation afterwards. Orphaned rows are only reported, not rejected: they
+// have already been inserted into the relation when the reports are returned.
+func (c ColumnStore) Load(relName string, csvFile string, separator rune) ([]OrphanReport, error) {
+	r := c.GetRelation(relName)
+	if r == nil {
+		return nil, fmt.Errorf("relation %s not found", relName)
+	}
+	r.Load(csvFile, separator)
+
+	reports := []OrphanReport{}
+	for _, fk := range c.ForeignKeys {
+		if fk.Relation != relName {
+			continue
+		}
+
+		report, err := c.validateForeignKey(fk)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
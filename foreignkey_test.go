@@ -0,0 +1,70 @@
+package csgo
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestColumnStoreForeignKeys(t *testing.T) {
+	cs := ColumnStore{}
+	part := cs.CreateRelation("PART", []AttrInfo{{"PARTKEY", INT, NOCOMP, PRIMARYKEY}, {"NAME", STRING, NOCOMP, 0}}).(Relation)
+	supplier := cs.CreateRelation("SUPPLIER", []AttrInfo{{"SUPPKEY", INT, NOCOMP, PRIMARYKEY}}).(Relation)
+	cs.CreateRelation("PARTSUPP", []AttrInfo{{"PARTKEY", INT, NOCOMP, 0}, {"SUPPKEY", INT, RLE, 0}, {"COMMENT", STRING, NOCOMP, 0}})
+
+	for _, key := range []int{1, 2, 3} {
+		part.AddRow(key, "part")
+	}
+	supplier.AddRow(10)
+	supplier.AddRow(20)
+
+	invalid := []ForeignKey{
+		{Relation: "PARTSUPP", Columns: []string{"PARTKEY"}, Referenced: "LINEITEM"},
+		{Relation: "PARTSUPP", Columns: []string{"PARTKEY", "SUPPKEY"}, Referenced: "PART"},
+		{Relation: "PARTSUPP", Columns: []string{"COMMENT"}, Referenced: "PART"},
+		{Relation: "PARTSUPP", Columns: []string{"MISSING"}, Referenced: "PART"},
+		{Relation: "PART", Columns: []string{"PARTKEY"}, Referenced: "PARTSUPP"},
+	}
+	for fkID, fk := range invalid {
+		if err := cs.DeclareForeignKey(fk); err == nil {
+			t.Errorf("invalid foreign key %d was declared", fkID)
+		}
+	}
+
+	partKey := ForeignKey{Relation: "PARTSUPP", Columns: []string{"PARTKEY"}, Referenced: "PART"}
+	suppKey := ForeignKey{Relation: "PARTSUPP", Columns: []string{"SUPPKEY"}, Referenced: "SUPPLIER"}
+	if cs.DeclareForeignKey(partKey) != nil || cs.DeclareForeignKey(suppKey) != nil || cs.DeclareForeignKey(partKey) == nil {
+		t.Fatalf("unexpected declarations %v", cs.ForeignKeys)
+	}
+
+	file, err := os.Create("partsupp.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("1,10,a\n4,10,b\n2,30,c\n3,20,d\n5,20,e")
+	file.Close()
+	defer os.Remove("partsupp.csv")
+
+	reports, err := cs.Load("PARTSUPP", "partsupp.csv", ',')
+	expected := []OrphanReport{
+		{ForeignKey: partKey, NumRows: 5, Rows: []int{1, 4}},
+		{ForeignKey: suppKey, NumRows: 5, Rows: []int{2}},
+	}
+	if err != nil || !reflect.DeepEqual(reports, expected) {
+		t.Errorf("unexpected reports %v (%v)", reports, err)
+	}
+	if reports[0].String() != "PARTSUPP(PARTKEY) -> PART: 2 of 5 rows orphaned (rows 1, 4)" {
+		t.Errorf("unexpected description %q", reports[0].String())
+	}
+
+	// the missing part gets added
+	part.AddRow(4, "part")
+	reports, _ = cs.ValidateForeignKeys()
+	if len(reports) != 2 || !reflect.DeepEqual(reports[0].Rows, []int{4}) {
+		t.Errorf("unexpected reports %v", reports)
+	}
+
+	if _, err := cs.Load("LINEITEM", "partsupp.csv", ','); err == nil {
+		t.Error("loading a missing relation succeeded")
+	}
+}
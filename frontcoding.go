@@ -0,0 +1,162 @@
+package csgo
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// FrontCodingBlockSize is the number of strings per block of a FrontCodedDataStore.
+const FrontCodingBlockSize = 16
+
+// FrontCodedDataStore is a DataStore for ungrouped STRING data applying front coding: every string
+// is stored as the length of the prefix it shares with its predecessor followed by the remaining
+// suffix. This works well for sorted or otherwise similar strings. The first string of every block
+// is stored completely, so random access only has to decode a single block.
+type FrontCodedDataStore struct {
+	// Data contains the encoded strings: the prefix length and the suffix length (both as unsigned
+	// varints) followed by the suffix.
+	Data []byte
+	// BlockOffsets contains the offset of the first string of every block within Data.
+	BlockOffsets []int
+	// Last is the last added string (needed for appending).
+	Last    string
+	NumRows int
+}
+
+// NewFrontCodedDataStore creates a new FrontCodedDataStore.
+func NewFrontCodedDataStore() DataStore {
+	return &FrontCodedDataStore{Data: []byte{}, BlockOffsets: []int{}}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *FrontCodedDataStore) GetDataType() DataTypes {
+	return STRING
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *FrontCodedDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// appendValue encodes str and adds it to the data.
+func (ds *FrontCodedDataStore) appendValue(str string) {
+	prefix := 0
+	if ds.NumRows%FrontCodingBlockSize == 0 {
+		ds.BlockOffsets = append(ds.BlockOffsets, len(ds.Data))
+	} else {
+		for prefix < len(str) && prefix < len(ds.Last) && str[prefix] == ds.Last[prefix] {
+			prefix++
+		}
+	}
+
+	ds.Data = binary.AppendUvarint(ds.Data, uint64(prefix))
+	ds.Data = binary.AppendUvarint(ds.Data, uint64(len(str)-prefix))
+	ds.Data = append(ds.Data, str[prefix:]...)
+	ds.Last = str
+	ds.NumRows++
+}
+
+// frontCodingReader decodes the strings of a FrontCodedDataStore sequentially.
+type frontCodingReader struct {
+	ds    *FrontCodedDataStore
+	row   int
+	pos   int
+	value []byte
+}
+
+// newFrontCodingReader creates a frontCodingReader positioned at the beginning of the block
+// containing rowIndex.
+func newFrontCodingReader(ds *FrontCodedDataStore, rowIndex int) *frontCodingReader {
+	block := rowIndex / FrontCodingBlockSize
+	return &frontCodingReader{ds: ds, row: block * FrontCodingBlockSize, pos: ds.BlockOffsets[block]}
+}
+
+// next decodes the string of the current row and advances to the next row.
+func (reader *frontCodingReader) next() string {
+	prefix, n := binary.Uvarint(reader.ds.Data[reader.pos:])
+	reader.pos += n
+	suffix, n := binary.Uvarint(reader.ds.Data[reader.pos:])
+	reader.pos += n
+
+	reader.value = append(reader.value[:prefix], reader.ds.Data[reader.pos:reader.pos+int(suffix)]...)
+	reader.pos += int(suffix)
+	reader.row++
+	return string(reader.value)
+}
+
+// AddRow adds a new row to the column.
+func (ds *FrontCodedDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != STRING {
+		return -1, errors.New("invalid type")
+	}
+
+	str, rightType := value.(string)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	ds.appendValue(str)
+	return ds.NumRows - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *FrontCodedDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= ds.NumRows {
+		return nil, errors.New("index out of bounds")
+	}
+
+	reader := newFrontCodingReader(ds, rowIndex)
+	for reader.row < rowIndex {
+		reader.next()
+	}
+	return reader.next(), nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *FrontCodedDataStore) GetNumRows() int {
+	return ds.NumRows
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+func (ds *FrontCodedDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != STRING {
+		return -1, errors.New("invalid type")
+	}
+
+	strings, rightType := values.([]string)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := ds.NumRows
+	for _, str := range strings {
+		ds.appendValue(str)
+	}
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end), which are decoded sequentially.
+func (ds *FrontCodedDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, ds.NumRows); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, end-start)
+	if start == end {
+		return values, nil
+	}
+
+	reader := newFrontCodingReader(ds, start)
+	for reader.row < start {
+		reader.next()
+	}
+	for reader.row < end {
+		values = append(values, reader.next())
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *FrontCodedDataStore) MemoryUsage() int {
+	return 2*sliceHeaderBytes + cap(ds.Data) + cap(ds.BlockOffsets)*wordBytes + valueBytes(ds.Last) + wordBytes
+}
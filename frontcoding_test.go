@@ -0,0 +1,67 @@
+package csgo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func createFrontCodedDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewFrontCodedDataStore(), "Customer#1", "Customer#12", "", "Customer#2"),
+		fillDataStore(NewFrontCodedDataStore(), "a", "a", "ab"),
+		NewFrontCodedDataStore(),
+	}
+}
+
+func TestFrontCodedDataStoreAddRow(t *testing.T) {
+	for _, ds := range createFrontCodedDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestFrontCodedDataStoreGetRow(t *testing.T) {
+	for _, ds := range createFrontCodedDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestFrontCodedDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createFrontCodedDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestFrontCodedDataStoreAddRowsGetRange(t *testing.T) {
+	// sorted names like in the TPC-H data
+	values := make([]string, 3*FrontCodingBlockSize+5)
+	for i := range values {
+		values[i] = fmt.Sprintf("Customer#%09d", i*7)
+	}
+	values[FrontCodingBlockSize+3] = ""
+
+	for _, ds := range createFrontCodedDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	ds := NewFrontCodedDataStore().(*FrontCodedDataStore)
+	ds.AddRows(STRING, values)
+	if len(ds.Data) > len(values)*len(values[0])/2 || len(ds.BlockOffsets) != 4 {
+		t.Errorf("%d values need %d bytes in %d blocks", len(values), len(ds.Data), len(ds.BlockOffsets))
+	}
+
+	for _, start := range []int{0, 3, FrontCodingBlockSize, FrontCodingBlockSize + 4, len(values) - 1} {
+		data, _ := ds.GetRange(start, len(values))
+		if !reflect.DeepEqual(data, values[start:]) {
+			t.Errorf("range starting at %d does not match", start)
+		}
+	}
+
+	// the inner layer of a layered encoding is ignored
+	for _, enc := range []Compression{FRONTCODING, FRONTCODING.Layered(RLE)} {
+		col := NewColumnWithData(AttrInfo{"col", STRING, enc, 0}, values)
+		if _, isFrontCoded := col.Data.(*FrontCodedDataStore); !isFrontCoded || !reflect.DeepEqual(col.GetRawData(), values) {
+			t.Errorf("front coded column using %v does not match", enc)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package csgo
+
+import "math"
+
+// hashJoinPartitionSize is the targeted number of build rows per partition. Partitions of this
+// size keep the per-partition hash tables small enough to stay in the CPU caches.
+const hashJoinPartitionSize = 4096
+
+// hashValue returns a 64 bit hash for a single (ungrouped) column value.
+func hashValue(value interface{}) uint64 {
+	switch v := value.(type) {
+	case int:
+		return hashInt(v)
+	case float64:
+		return hashFloat(v)
+	case string:
+		return hashString(v)
+	}
+	panic("unknown failure (type unknown?)")
+}
+
+// hashInt returns a 64 bit hash for an INT value.
+func hashInt(value int) uint64 {
+	return mixHash(uint64(value))
+}
+
+// hashFloat returns a 64 bit hash for a FLOAT value.
+func hashFloat(value float64) uint64 {
+	if value == 0 {
+		// make sure that -0.0 and +0.0 end up in the same bucket
+		value = 0
+	}
+	return mixHash(math.Float64bits(value))
+}
+
+// hashString returns a 64 bit hash (FNV-1a) for a STRING value.
+func hashString(value string) uint64 {
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(value); i++ {
+		hash ^= uint64(value[i])
+		hash *= 1099511628211
+	}
+	return mixHash(hash)
+}
+
+// mixHash is the finalizer of splitmix64, it spreads the entropy of the input over all bits.
+func mixHash(hash uint64) uint64 {
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+	return hash
+}
+
+// ParallelHashJoin implements the hash join operator between two relations using radix
+// partitioning.
+// Both inputs get partitioned by the hash of their join keys, afterwards the hash tables of all
+// partitions are built and probed concurrently by up to numWorkers goroutines.
+// The output is identical to the output of a single threaded hash join (including row order).
+func (r Relation) ParallelHashJoin(col1 []AttrInfo, rightRelation Relationer, col2 []AttrInfo, joinType JoinType, compType Comparison, numWorkers int) Relationer {
+	if compType != EQ {
+		panic("HashJoin requires an equijoin predicate")
+	}
+
+	rightR, isRelation := (rightRelation).(Relation)
+
+	if !isRelation {
+		panic("unknown relation type")
+	}
+
+	if len(col1) == 0 || len(col1) != len(col2) {
+		panic("HashJoin requires the same number of join columns on both sides")
+	}
+
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	right := &rightR
+	left := &r
+
+	output := Relation{Name: r.Name + " x " + right.Name, Columns: []Column{}}
+
+	leftKeys := findJoinColumns(left, col1)
+	rightKeys := findJoinColumns(right, col2)
+
+	// the number of partitions is a power of two, so the partition of a row can be derived from
+	// the lowest bits of its hash
+	numPartitionsFor := func(buildRows int) int {
+		numPartitions := 1
+		for numPartitions < numWorkers || numPartitions*hashJoinPartitionSize < buildRows {
+			numPartitions <<= 1
+		}
+		return numPartitions
+	}
+
+	type partitionedInput struct {
+		keys       []*Column
+		hashes     []uint64
+		partitions [][]int
+	}
+
+	// partition computes the hash of every row and distributes the row indices among the
+	// partitions. Each partition lists its rows in ascending order.
+	partition := func(keys []*Column, numPartitions int) partitionedInput {
+		numRows := keys[0].GetNumRows()
+		input := partitionedInput{keys: keys, hashes: make([]uint64, numRows)}
+		chunks := splitRows(numRows, numWorkers)
+		chunkPartitions := make([][][]int, len(chunks))
+
+		parallelFor(len(chunks), numWorkers, func(chunkIndex int) {
+			partitions := make([][]int, numPartitions)
+			vec := Vector{}
+
+			// the hashes are computed batch by batch on the decompressed key vectors
+			for start := chunks[chunkIndex].Start; start < chunks[chunkIndex].End; start += BatchSize {
+				end := start + BatchSize
+				if end > chunks[chunkIndex].End {
+					end = chunks[chunkIndex].End
+				}
+
+				for _, key := range keys {
+					if err := readVector(key, start, end, &vec); err != nil {
+						panic(err)
+					}
+					hashVector(&vec, input.hashes[start:end])
+				}
+
+				for row := start; row < end; row++ {
+					partitionIndex := input.hashes[row] & uint64(numPartitions-1)
+					partitions[partitionIndex] = append(partitions[partitionIndex], row)
+				}
+			}
+
+			chunkPartitions[chunkIndex] = partitions
+		})
+
+		input.partitions = make([][]int, numPartitions)
+		for partitionIndex := range input.partitions {
+			for _, partitions := range chunkPartitions {
+				input.partitions[partitionIndex] = append(input.partitions[partitionIndex], partitions[partitionIndex]...)
+			}
+		}
+
+		return input
+	}
+
+	keysEqual := func(build *partitionedInput, buildRow int, probe *partitionedInput, probeRow int) bool {
+		for keyIndex := range build.keys {
+			buildValue, _ := build.keys[keyIndex].GetRow(buildRow)
+			probeValue, _ := probe.keys[keyIndex].GetRow(probeRow)
+			if buildValue != probeValue {
+				return false
+			}
+		}
+		return true
+	}
+
+	// join returns the matching build rows (in ascending order) for every row of the probe side
+	join := func(buildKeys []*Column, probeKeys []*Column) [][]int {
+		numPartitions := numPartitionsFor(buildKeys[0].GetNumRows())
+		build := partition(buildKeys, numPartitions)
+		probe := partition(probeKeys, numPartitions)
+		matches := make([][]int, probeKeys[0].GetNumRows())
+
+		parallelFor(numPartitions, numWorkers, func(partitionIndex int) {
+			buildRows := build.partitions[partitionIndex]
+			if len(buildRows) == 0 {
+				return
+			}
+
+			hashTable := make(map[uint64][]int, len(buildRows))
+			for _, row := range buildRows {
+				hashTable[build.hashes[row]] = append(hashTable[build.hashes[row]], row)
+			}
+
+			for _, probeRow := range probe.partitions[partitionIndex] {
+				for _, buildRow := range hashTable[probe.hashes[probeRow]] {
+					if keysEqual(&build, buildRow, &probe, probeRow) {
+						// every probe row belongs to exactly one partition, so there are no
+						// concurrent writes to the same slice element
+						matches[probeRow] = append(matches[probeRow], buildRow)
+					}
+				}
+			}
+		})
+
+		return matches
+	}
+
+	maxLeftRows := left.Columns[0].GetNumRows()
+	maxRightRows := right.Columns[0].GetNumRows()
+	leftIndices := []int{}
+	rightIndices := []int{}
+
+	innerJoin := func() {
+		if maxLeftRows < maxRightRows {
+			for rightRow, matches := range join(leftKeys, rightKeys) {
+				for _, leftRow := range matches {
+					leftIndices = append(leftIndices, leftRow)
+					rightIndices = append(rightIndices, rightRow)
+				}
+			}
+		} else {
+			for leftRow, matches := range join(rightKeys, leftKeys) {
+				for _, rightRow := range matches {
+					leftIndices = append(leftIndices, leftRow)
+					rightIndices = append(rightIndices, rightRow)
+				}
+			}
+		}
+	}
+
+	semiJoin := func() {
+		for leftRow, matches := range join(rightKeys, leftKeys) {
+			if len(matches) > 0 {
+				leftIndices = append(leftIndices, leftRow)
+			}
+		}
+	}
+
+	switch joinType {
+	case INNER:
+		innerJoin()
+		output.Columns = append(output.Columns, joinOutputColumns(left, left.Name, leftIndices)...)
+		output.Columns = append(output.Columns, joinOutputColumns(right, right.Name, rightIndices)...)
+	case SEMI:
+		output.Name = left.Name + " (x " + right.Name + ")"
+		semiJoin()
+		output.Columns = append(output.Columns, joinOutputColumns(left, left.Name, leftIndices)...)
+	case RIGHTOUTER:
+		panic("NULL values not implemented")
+	case LEFTOUTER:
+		panic("NULL values not implemented")
+	default:
+		panic("unknown join type")
+	}
+
+	return output
+}
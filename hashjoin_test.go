@@ -0,0 +1,87 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelationParallelHashJoin(t *testing.T) {
+	leftKeys := []int{}
+	leftValues := []string{}
+	for i := 0; i < 6000; i++ {
+		leftKeys = append(leftKeys, (i*7)%5003)
+		leftValues = append(leftValues, string(rune('a'+i%26)))
+	}
+
+	rightKeys := []int{}
+	rightValues := []float64{}
+	for i := 0; i < 2500; i++ {
+		rightKeys = append(rightKeys, (i*13)%7919)
+		rightValues = append(rightValues, float64(i)/2)
+	}
+
+	left := Relation{Name: "left", Columns: []Column{
+		NewColumnWithData(AttrInfo{"key", INT, NOCOMP, 0}, leftKeys),
+		NewColumnWithData(AttrInfo{"value", STRING, NOCOMP, 0}, leftValues),
+	}}
+	right := Relation{Name: "right", Columns: []Column{
+		NewColumnWithData(AttrInfo{"key", INT, NOCOMP, 0}, rightKeys),
+		NewColumnWithData(AttrInfo{"value", FLOAT, NOCOMP, 0}, rightValues),
+	}}
+
+	// expected results via nested loops (the right relation is the smaller one, so the output is
+	// ordered by the left rows)
+	expectedLeft := []int{}
+	expectedRight := []int{}
+	expectedSemi := []int{}
+	for leftRow, leftKey := range leftKeys {
+		found := false
+		for rightRow, rightKey := range rightKeys {
+			if leftKey == rightKey {
+				expectedLeft = append(expectedLeft, leftRow)
+				expectedRight = append(expectedRight, rightRow)
+				found = true
+			}
+		}
+		if found {
+			expectedSemi = append(expectedSemi, leftRow)
+		}
+	}
+
+	pick := func(values interface{}, rows []int) interface{} {
+		source := reflect.ValueOf(values)
+		output := reflect.MakeSlice(source.Type(), 0, len(rows))
+		for _, row := range rows {
+			output = reflect.Append(output, source.Index(row))
+		}
+		return output.Interface()
+	}
+
+	cols := []AttrInfo{{"key", INT, NOCOMP, 0}}
+
+	for _, numWorkers := range []int{1, 2, 3, 8} {
+		output := left.ParallelHashJoin(cols, right, cols, INNER, EQ, numWorkers)
+		data, _ := output.GetRawData()
+		expected := []interface{}{
+			pick(leftKeys, expectedLeft),
+			pick(leftValues, expectedLeft),
+			pick(rightKeys, expectedRight),
+			pick(rightValues, expectedRight),
+		}
+
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("inner join with %d workers does not match the expected output", numWorkers)
+		}
+
+		output = left.ParallelHashJoin(cols, right, cols, SEMI, EQ, numWorkers)
+		data, _ = output.GetRawData()
+		expected = []interface{}{
+			pick(leftKeys, expectedSemi),
+			pick(leftValues, expectedSemi),
+		}
+
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("semi join with %d workers does not match the expected output", numWorkers)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package csgo
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Index is a search structure over the values of an ungrouped column. Indexes are kept up to date
+// as rows are appended via Column.AddRow or Column.AddRows and used by Select automatically.
+type Index interface {
+	// Add adds the value of the row rowIndex. Rows have to be added in order, others are ignored.
+	Add(rowIndex int, value interface{})
+	// GetNumRows returns the number of indexed rows.
+	GetNumRows() int
+	// Supports reports whether Lookup can evaluate the given comparison.
+	Supports(comp Comparison) bool
+	// Lookup returns the indices of all rows whose value satisfies the predicate (value comp compVal)
+	// in ascending order.
+	Lookup(comp Comparison, compVal interface{}) []int
+	// MemoryUsage returns the estimated number of bytes used by the index.
+	MemoryUsage() int
+}
+
+// newIndexes creates the (empty) indexes declared by the flags of sig. Grouped columns can't be
+// indexed.
+func newIndexes(sig AttrInfo) []Index {
+	if sig.Flags&GROUPED != 0 {
+		return nil
+	}
+
+	var indexes []Index
+	// key constraints are checked using the hash index
+	if sig.Flags&(HASHINDEX|PRIMARYKEY|UNIQUE) != 0 {
+		indexes = append(indexes, NewHashIndex(sig.Type))
+	}
+	if sig.Flags&ORDEREDINDEX != 0 {
+		indexes = append(indexes, NewOrderedIndex(sig.Type))
+	}
+	if sig.Flags&BITMAPINDEX != 0 {
+		indexes = append(indexes, NewBitmapIndex(sig.Type))
+	}
+	return indexes
+}
+
+// lookupIndex evaluates the predicate (value comp compVal) using an index of the column. found is
+// false if no index covering all rows supports the predicate. Predicates on NaN are left to a scan.
+func (col *Column) lookupIndex(comp Comparison, compVal interface{}) (rows []int, found bool) {
+	if !isPredicateValue(col.Signature.Type, comp, compVal) || isNaN(compVal) {
+		return nil, false
+	}
+
+	for _, index := range col.Indexes {
+		if index.Supports(comp) && index.GetNumRows() == col.GetNumRows() {
+			return index.Lookup(comp, compVal), true
+		}
+	}
+	return nil, false
+}
+
+// lookupBitmap evaluates the predicate (value comp compVal) using a bitmap index of the column.
+// found is false if the column has no bitmap index covering all rows and supporting the predicate.
+func (col *Column) lookupBitmap(comp Comparison, compVal interface{}) (matches Bitmap, found bool) {
+	if !isPredicateValue(col.Signature.Type, comp, compVal) {
+		return Bitmap{}, false
+	}
+
+	for _, index := range col.Indexes {
+		if bitmapIndex, isBitmap := index.(*BitmapIndex); isBitmap && bitmapIndex.Supports(comp) && bitmapIndex.GetNumRows() == col.GetNumRows() {
+			return bitmapIndex.LookupBitmap(comp, compVal), true
+		}
+	}
+	return Bitmap{}, false
+}
+
+// indexFlags returns the flags of the kinds of indexes the column already has (e.g. HASHINDEX for
+// the hash index of a PRIMARYKEY or UNIQUE column).
+func (col *Column) indexFlags() ColumnFlags {
+	var flags ColumnFlags
+	for _, index := range col.Indexes {
+		switch index.(type) {
+		case *HashIndex:
+			flags |= HASHINDEX
+		case *OrderedIndex:
+			flags |= ORDEREDINDEX
+		case *BitmapIndex:
+			flags |= BITMAPINDEX
+		}
+	}
+	return flags
+}
+
+// isPredicateValue reports whether compVal can be compared with values of the type typ using comp
+// (IN predicates need a slice of such values).
+func isPredicateValue(typ DataTypes, comp Comparison, compVal interface{}) bool {
+	if comp == IN {
+		_, err := boxValues(typ, 0, compVal)
+		return err == nil
+	}
+	return hasType(typ, compVal)
+}
+
+// CreateIndex adds the indexes given by flags (HASHINDEX, ORDEREDINDEX and/or BITMAPINDEX) to the
+// column col and returns the new signature of the column. Kinds of indexes the column already has
+// aren't created again. The column is modified in place, so all
+// relations sharing the columns of r use the new indexes. The original signature still references
+// the column.
+func (r Relation) CreateIndex(col AttrInfo, flags ColumnFlags) (AttrInfo, error) {
+	if flags == 0 || flags&^(HASHINDEX|ORDEREDINDEX|BITMAPINDEX) != 0 {
+		return col, errors.New("invalid index flags")
+	}
+
+	for colIndex := range r.Columns {
+		if !r.Columns[colIndex].Signature.matches(col) {
+			continue
+		}
+
+		signature := r.Columns[colIndex].Signature
+		if signature.Flags&GROUPED != 0 {
+			return col, errors.New("grouped columns can't be indexed")
+		}
+
+		added := newIndexes(AttrInfo{Type: signature.Type, Flags: flags &^ r.Columns[colIndex].indexFlags()})
+		signature.Flags |= flags
+
+		values, err := boxValues(signature.Type, 0, r.Columns[colIndex].GetRawData())
+		if err != nil {
+			return col, err
+		}
+		for _, index := range added {
+			for rowIndex, value := range values {
+				index.Add(rowIndex, value)
+			}
+		}
+
+		r.Columns[colIndex].Signature = signature
+		r.Columns[colIndex].Indexes = append(r.Columns[colIndex].Indexes, added...)
+		return signature, nil
+	}
+
+	return col, errors.New("column not found")
+}
+
+// HashIndex maps every value of a column to the rows containing it. It supports EQ and IN
+// predicates.
+type HashIndex struct {
+	DataType DataTypes
+	// Rows contains the row indices (in ascending order) of every value.
+	Rows    map[interface{}][]int
+	NumRows int
+}
+
+// NewHashIndex creates an empty HashIndex for values of the given type.
+func NewHashIndex(typ DataTypes) Index {
+	return &HashIndex{DataType: typ, Rows: map[interface{}][]int{}}
+}
+
+// Add adds the value of the row rowIndex.
+func (index *HashIndex) Add(rowIndex int, value interface{}) {
+	if rowIndex != index.NumRows {
+		return
+	}
+
+	index.Rows[value] = append(index.Rows[value], rowIndex)
+	index.NumRows++
+}
+
+// GetNumRows returns the number of indexed rows.
+func (index *HashIndex) GetNumRows() int {
+	return index.NumRows
+}
+
+// Supports reports whether Lookup can evaluate the given comparison.
+func (index *HashIndex) Supports(comp Comparison) bool {
+	return comp == EQ || comp == IN
+}
+
+// Lookup returns the indices of all rows whose value equals compVal (or one of the values of
+// compVal for IN predicates).
+func (index *HashIndex) Lookup(comp Comparison, compVal interface{}) []int {
+	switch comp {
+	case EQ:
+		return append([]int{}, index.Rows[compVal]...)
+	case IN:
+		values, _ := boxValues(index.DataType, 0, compVal)
+		rows := []int{}
+		for value := range distinctValues(values) {
+			rows = append(rows, index.Rows[value]...)
+		}
+		sort.Ints(rows)
+		return rows
+	}
+	panic("comparison not supported by hash index")
+}
+
+// distinctValues returns the set of distinct values.
+func distinctValues(values []interface{}) map[interface{}]bool {
+	distinct := map[interface{}]bool{}
+	for _, value := range values {
+		distinct[value] = true
+	}
+	return distinct
+}
+
+// MemoryUsage returns the estimated number of bytes used by the index.
+func (index *HashIndex) MemoryUsage() int {
+	size := 3 * wordBytes
+	for value, rows := range index.Rows {
+		size += mapEntryBytes + interfaceBytes + valueBytes(value) + sliceHeaderBytes + cap(rows)*wordBytes
+	}
+	return size
+}
+
+// OrderedIndex is a sorted permutation of the rows of a column. It supports all comparisons.
+// Added rows are collected and only merged into the permutation on the next lookup, so loading
+// doesn't need to keep the permutation sorted after every single row. NaN values can't be ordered,
+// so their rows are kept apart and only match NEQ predicates.
+type OrderedIndex struct {
+	DataType DataTypes
+	// Values and Rows contain the values and row indices of the merged rows, sorted by value (and
+	// row index for equal values).
+	Values []interface{}
+	Rows   []int
+	// NaNRows contains the merged rows whose value is NaN in ascending order.
+	NaNRows []int
+	// Pending contains the values of the rows added since the last merge
+	// (rows [NumRows-len(Pending), NumRows)).
+	Pending []interface{}
+	NumRows int
+
+	mutex sync.Mutex
+}
+
+// NewOrderedIndex creates an empty OrderedIndex for values of the given type.
+func NewOrderedIndex(typ DataTypes) Index {
+	return &OrderedIndex{DataType: typ, Values: []interface{}{}, Rows: []int{}, NaNRows: []int{}, Pending: []interface{}{}}
+}
+
+// Add adds the value of the row rowIndex.
+func (index *OrderedIndex) Add(rowIndex int, value interface{}) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if rowIndex != index.NumRows {
+		return
+	}
+
+	index.Pending = append(index.Pending, value)
+	index.NumRows++
+}
+
+// GetNumRows returns the number of indexed rows.
+func (index *OrderedIndex) GetNumRows() int {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	return index.NumRows
+}
+
+// Supports reports whether Lookup can evaluate the given comparison.
+func (index *OrderedIndex) Supports(comp Comparison) bool {
+	_, found := compFuncs[index.DataType][comp]
+	return found
+}
+
+// merge sorts the pending rows and merges them into the permutation. The mutex has to be held.
+func (index *OrderedIndex) merge() {
+	if len(index.Pending) == 0 {
+		return
+	}
+
+	less := compFuncs[index.DataType][LT]
+	firstPending := index.NumRows - len(index.Pending)
+
+	pending := make([]int, 0, len(index.Pending))
+	for i, value := range index.Pending {
+		if isNaN(value) {
+			index.NaNRows = append(index.NaNRows, firstPending+i)
+			continue
+		}
+		pending = append(pending, i)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return less(index.Pending[pending[i]], index.Pending[pending[j]])
+	})
+
+	values := make([]interface{}, 0, index.NumRows)
+	rows := make([]int, 0, index.NumRows)
+	merged := 0
+	for _, i := range pending {
+		// merged rows have smaller row indices, so they come first for equal values
+		for merged < len(index.Rows) && !less(index.Pending[i], index.Values[merged]) {
+			values = append(values, index.Values[merged])
+			rows = append(rows, index.Rows[merged])
+			merged++
+		}
+		values = append(values, index.Pending[i])
+		rows = append(rows, firstPending+i)
+	}
+	values = append(values, index.Values[merged:]...)
+	rows = append(rows, index.Rows[merged:]...)
+
+	index.Values, index.Rows = values, rows
+	index.Pending = index.Pending[:0]
+}
+
+// Lookup returns the indices of all rows whose value satisfies the predicate (value comp compVal)
+// in ascending order.
+func (index *OrderedIndex) Lookup(comp Comparison, compVal interface{}) []int {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.merge()
+
+	// NaN doesn't equal or compare to any value
+	if isNaN(compVal) {
+		if comp != NEQ {
+			return []int{}
+		}
+		rows := append(append([]int{}, index.Rows...), index.NaNRows...)
+		sort.Ints(rows)
+		return rows
+	}
+
+	less := compFuncs[index.DataType][LT]
+	// the values equal to compVal are located at [lower, upper)
+	lower := sort.Search(len(index.Values), func(i int) bool { return !less(index.Values[i], compVal) })
+	upper := sort.Search(len(index.Values), func(i int) bool { return less(compVal, index.Values[i]) })
+
+	rows := []int{}
+	for _, matches := range sortedRanges(comp, lower, upper, len(index.Rows)) {
+		rows = append(rows, index.Rows[matches.Start:matches.End]...)
+	}
+	// NaN isn't equal to any value
+	if comp == NEQ {
+		rows = append(rows, index.NaNRows...)
+	}
+
+	sort.Ints(rows)
+	return rows
+}
+
+// sortedRanges returns the ranges of positions within sorted values satisfying the predicate
+// (value comp compVal), given that the values equal to compVal are located at [lower, upper).
+func sortedRanges(comp Comparison, lower int, upper int, numValues int) []rowRange {
+	switch comp {
+	case EQ:
+		return []rowRange{{lower, upper}}
+	case NEQ:
+		return []rowRange{{0, lower}, {upper, numValues}}
+	case LT:
+		return []rowRange{{0, lower}}
+	case LEQ:
+		return []rowRange{{0, upper}}
+	case GT:
+		return []rowRange{{upper, numValues}}
+	case GEQ:
+		return []rowRange{{lower, numValues}}
+	}
+	panic("comparison not supported on sorted values")
+}
+
+// sortedRows evaluates the predicate (value comp compVal) on a SORTED column using binary search.
+// found is false if the predicate can't be evaluated this way, e.g. for NaN, which isn't ordered.
+func (col *Column) sortedRows(comp Comparison, compVal interface{}) (rows []int, found bool, err error) {
+	if col.Signature.Flags&SORTED == 0 || col.Signature.Flags&GROUPED != 0 || comp == IN || !hasType(col.Signature.Type, compVal) || isNaN(compVal) {
+		return nil, false, nil
+	}
+	if _, found := compFuncs[col.Signature.Type][comp]; !found {
+		return nil, false, nil
+	}
+
+	less := compFuncs[col.Signature.Type][LT]
+	// search stops at the first row which can't be read, err is returned after the searches
+	search := func(numRows int, pred func(value interface{}) bool) int {
+		return sort.Search(numRows, func(row int) bool {
+			if err != nil {
+				return true
+			}
+			value, rowErr := col.GetRow(row)
+			if rowErr != nil {
+				err = rowErr
+				return true
+			}
+			return pred(value)
+		})
+	}
+
+	numRows := col.GetNumRows()
+	// the values equal to compVal are located at [lower, upper)
+	lower := search(numRows, func(value interface{}) bool { return !less(value, compVal) })
+	upper := search(numRows, func(value interface{}) bool { return less(compVal, value) })
+	if err != nil {
+		return nil, true, err
+	}
+
+	rows = []int{}
+	for _, matches := range sortedRanges(comp, lower, upper, numRows) {
+		for row := matches.Start; row < matches.End; row++ {
+			rows = append(rows, row)
+		}
+	}
+	return rows, true, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the index.
+func (index *OrderedIndex) MemoryUsage() int {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	size := wordBytes + 4*sliceHeaderBytes + 2*wordBytes
+	size += cap(index.Values)*interfaceBytes + cap(index.Rows)*wordBytes + cap(index.NaNRows)*wordBytes + cap(index.Pending)*interfaceBytes
+	for _, value := range index.Values {
+		size += valueBytes(value)
+	}
+	for _, value := range index.Pending {
+		size += valueBytes(value)
+	}
+	return size
+}
+
+// BitmapIndex keeps a Bitmap of the matching rows for every distinct value of a column, which is
+// suited for columns with few distinct values. It supports EQ, NEQ and IN predicates, which are
+// answered by combining the bitmaps (see Relation.SelectWhere).
+type BitmapIndex struct {
+	DataType DataTypes
+	Bitmaps  map[interface{}]*Bitmap
+	NumRows  int
+}
+
+// NewBitmapIndex creates an empty BitmapIndex for values of the given type.
+func NewBitmapIndex(typ DataTypes) Index {
+	return &BitmapIndex{DataType: typ, Bitmaps: map[interface{}]*Bitmap{}}
+}
+
+// Add adds the value of the row rowIndex.
+func (index *BitmapIndex) Add(rowIndex int, value interface{}) {
+	if rowIndex != index.NumRows {
+		return
+	}
+
+	bitmap, found := index.Bitmaps[value]
+	if !found {
+		bitmap = &Bitmap{Containers: []BitmapContainer{}}
+		index.Bitmaps[value] = bitmap
+	}
+	bitmap.Add(rowIndex)
+	index.NumRows++
+}
+
+// GetNumRows returns the number of indexed rows.
+func (index *BitmapIndex) GetNumRows() int {
+	return index.NumRows
+}
+
+// Supports reports whether Lookup can evaluate the given comparison.
+func (index *BitmapIndex) Supports(comp Comparison) bool {
+	return comp == EQ || comp == NEQ || comp == IN
+}
+
+// LookupBitmap returns the bitmap of all rows whose value satisfies the predicate
+// (value comp compVal).
+func (index *BitmapIndex) LookupBitmap(comp Comparison, compVal interface{}) Bitmap {
+	matches := Bitmap{Containers: []BitmapContainer{}}
+
+	switch comp {
+	case EQ:
+		if bitmap, found := index.Bitmaps[compVal]; found {
+			matches = matches.Or(*bitmap)
+		}
+	case NEQ:
+		// all rows except the matching and the NULL rows
+		excluded := Bitmap{}
+		if bitmap, found := index.Bitmaps[compVal]; found {
+			excluded = excluded.Or(*bitmap)
+		}
+		if bitmap, found := index.Bitmaps[nil]; found {
+			excluded = excluded.Or(*bitmap)
+		}
+		matches = excluded.Complement(index.NumRows)
+	case IN:
+		values, _ := boxValues(index.DataType, 0, compVal)
+		for value := range distinctValues(values) {
+			if bitmap, found := index.Bitmaps[value]; found {
+				matches = matches.Or(*bitmap)
+			}
+		}
+	default:
+		panic("comparison not supported by bitmap index")
+	}
+
+	return matches
+}
+
+// Lookup returns the indices of all rows whose value satisfies the predicate (value comp compVal)
+// in ascending order.
+func (index *BitmapIndex) Lookup(comp Comparison, compVal interface{}) []int {
+	return index.LookupBitmap(comp, compVal).Rows()
+}
+
+// MemoryUsage returns the estimated number of bytes used by the index.
+func (index *BitmapIndex) MemoryUsage() int {
+	size := 3 * wordBytes
+	for value, bitmap := range index.Bitmaps {
+		size += mapEntryBytes + interfaceBytes + valueBytes(value) + wordBytes + bitmap.memoryUsage()
+	}
+	return size
+}
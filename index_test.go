@@ -0,0 +1,185 @@
+package csgo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestIndexLookup(t *testing.T) {
+	values := []int{5, 3, 5, 1, 9, 3, 3, 7, 0, 5}
+
+	for _, flags := range []ColumnFlags{HASHINDEX, ORDEREDINDEX, BITMAPINDEX} {
+		col := NewColumn(AttrInfo{"col", INT, NOCOMP, flags})
+		// lookups between the additions force the ordered index to merge several times
+		for i, value := range values {
+			col.AddRow(INT, value)
+			if i%3 == 0 {
+				col.lookupIndex(EQ, 0)
+			}
+		}
+
+		for _, comp := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+			for _, compVal := range []int{-1, 0, 3, 4, 5, 9, 10} {
+				rows, found := col.lookupIndex(comp, compVal)
+				if found != (flags == ORDEREDINDEX || comp == EQ || (flags == BITMAPINDEX && comp == NEQ)) {
+					t.Errorf("%v: unexpected index support for %v", flags, comp)
+					continue
+				}
+				if !found {
+					continue
+				}
+
+				expected := []int{}
+				for row, value := range values {
+					if compFuncs[INT][comp](value, compVal) {
+						expected = append(expected, row)
+					}
+				}
+				if !reflect.DeepEqual(rows, expected) {
+					t.Errorf("%v: lookup (%v %d) returned %v, expected %v", flags, comp, compVal, rows, expected)
+				}
+			}
+		}
+
+		rows, found := col.lookupIndex(IN, []int{9, 3, 42, 9})
+		if found == (flags == ORDEREDINDEX) || (found && !reflect.DeepEqual(rows, []int{1, 4, 5, 6})) {
+			t.Errorf("%v: unexpected IN lookup %v", flags, rows)
+		}
+
+		if _, found := col.lookupIndex(EQ, "5"); found {
+			t.Errorf("%v: lookup with mismatching type succeeded", flags)
+		}
+
+		// rows added bypassing the column make the index incomplete
+		col.Data.(DataStore).AddRow(INT, 5)
+		if _, found := col.lookupIndex(EQ, 5); found {
+			t.Errorf("%v: incomplete index was used", flags)
+		}
+	}
+
+	// NULL rows never match NEQ
+	bitmapIndex := NewBitmapIndex(INT).(*BitmapIndex)
+	for row, value := range []interface{}{1, nil, 2, 1, nil} {
+		bitmapIndex.Add(row, value)
+	}
+	if rows := bitmapIndex.Lookup(NEQ, 1); !reflect.DeepEqual(rows, []int{2}) {
+		t.Errorf("unexpected NEQ lookup %v", rows)
+	}
+	if rows := bitmapIndex.Lookup(NEQ, 3); !reflect.DeepEqual(rows, []int{0, 2, 3}) {
+		t.Errorf("unexpected NEQ lookup %v", rows)
+	}
+
+	strings := NewColumnWithData(AttrInfo{"col", STRING, DICT, HASHINDEX | ORDEREDINDEX}, []string{"b", "a", "c", "a"})
+	if _, isDict := strings.Data.(*DictEncodedDataStore); !isDict || len(strings.Indexes) != 2 {
+		t.Error("indexed dictionary column was not created")
+	}
+	if rows, _ := strings.lookupIndex(GT, "a"); !reflect.DeepEqual(rows, []int{0, 2}) {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestOrderedIndexNaN(t *testing.T) {
+	nan := math.NaN()
+	values := []float64{1, nan, 0.5, 2, 3, nan, 0.1}
+
+	col := NewColumn(AttrInfo{"col", FLOAT, NOCOMP, ORDEREDINDEX})
+	// merging between the additions checks that NaN rows keep their row indices
+	for i, value := range values {
+		col.AddRow(FLOAT, value)
+		if i == 2 {
+			col.lookupIndex(EQ, 0.0)
+		}
+	}
+
+	for _, comp := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+		for _, compVal := range []float64{0, 0.5, 1.5, 3, 4, nan} {
+			expected := []int{}
+			for row, value := range values {
+				if compFuncs[FLOAT][comp](value, compVal) {
+					expected = append(expected, row)
+				}
+			}
+
+			rows, found := col.lookupIndex(comp, compVal)
+			if found != !math.IsNaN(compVal) {
+				t.Errorf("unexpected index support for (%v %v)", comp, compVal)
+			}
+			if found && !reflect.DeepEqual(rows, expected) {
+				t.Errorf("lookup (%v %v) returned %v, expected %v", comp, compVal, rows, expected)
+			}
+			if rows := col.Indexes[0].Lookup(comp, compVal); !reflect.DeepEqual(rows, expected) {
+				t.Errorf("index lookup (%v %v) returned %v, expected %v", comp, compVal, rows, expected)
+			}
+			if rows, err := col.selectRows(comp, compVal, 1); err != nil || !reflect.DeepEqual(rows, expected) {
+				t.Errorf("selection (%v %v) returned %v, expected %v", comp, compVal, rows, expected)
+			}
+		}
+	}
+}
+
+func TestRelationCreateIndex(t *testing.T) {
+	keys := make([]int, 3000)
+	for i := range keys {
+		keys[i] = (i * 7) % 1000
+	}
+	sig := AttrInfo{"key", INT, NOCOMP, 0}
+	r := Relation{Name: "rel", Columns: []Column{
+		NewColumnWithData(sig, keys),
+		NewColumnWithData(AttrInfo{"value", INT, NOCOMP, 0}, keys),
+	}}
+
+	expected, _ := r.Select(sig, LT, 10).GetRawData()
+
+	indexed, err := r.CreateIndex(sig, ORDEREDINDEX)
+	if err != nil || indexed != (AttrInfo{"key", INT, NOCOMP, ORDEREDINDEX}) || len(r.Columns[0].Indexes) != 1 {
+		t.Fatalf("unexpected result %v (%v)", indexed, err)
+	}
+
+	if _, found := r.Columns[0].lookupIndex(LT, 10); !found {
+		t.Error("ordered index is not used")
+	}
+	output, _ := r.Select(indexed, LT, 10).GetRawData()
+	if !reflect.DeepEqual(output, expected) {
+		t.Error("selection using the index does not match the scan")
+	}
+
+	// the index is maintained when rows get added
+	r.Columns[0].AddRow(INT, 5)
+	r.Columns[1].AddRow(INT, -1)
+	output, _ = r.Select(indexed, EQ, 5).GetRawData()
+	if !reflect.DeepEqual(output[1], []int{5, 5, 5, -1}) {
+		t.Errorf("unexpected output %v", output)
+	}
+
+	// the original signature still references the indexed column
+	output, _ = r.Select(sig, EQ, 5).GetRawData()
+	if !reflect.DeepEqual(output[1], []int{5, 5, 5, -1}) {
+		t.Errorf("unexpected output %v using the original signature", output)
+	}
+	if indexed, err := r.CreateIndex(sig, HASHINDEX); err != nil || indexed.Flags != ORDEREDINDEX|HASHINDEX {
+		t.Errorf("adding an index using the original signature failed: %v", err)
+	}
+
+	if _, err := r.CreateIndex(AttrInfo{"missing", INT, NOCOMP, 0}, HASHINDEX); err == nil {
+		t.Error("index on a missing column was created")
+	}
+	if _, err := r.CreateIndex(indexed, GROUPED); err == nil {
+		t.Error("invalid index flags were accepted")
+	}
+
+	// existing indexes aren't created again, including the hash index of unique columns
+	if _, err := r.CreateIndex(sig, HASHINDEX|ORDEREDINDEX); err != nil || len(r.Columns[0].Indexes) != 2 {
+		t.Errorf("existing indexes were added again: %d indexes (%v)", len(r.Columns[0].Indexes), err)
+	}
+	uniqueSig := AttrInfo{"key", INT, NOCOMP, UNIQUE}
+	unique := Relation{Name: "rel", Columns: []Column{NewColumnWithData(uniqueSig, []int{3, 1, 2})}}
+	if indexed, err := unique.CreateIndex(uniqueSig, HASHINDEX); err != nil || indexed.Flags != UNIQUE|HASHINDEX || len(unique.Columns[0].Indexes) != 1 {
+		t.Errorf("hash index of the unique column was added again: %d indexes (%v)", len(unique.Columns[0].Indexes), err)
+	}
+
+	grouped := Relation{Name: "rel", Columns: []Column{NewColumn(AttrInfo{"key", INT, NOCOMP, GROUPED})}}
+	if _, err := grouped.CreateIndex(AttrInfo{"key", INT, NOCOMP, GROUPED}, HASHINDEX); err == nil {
+		t.Error("index on a grouped column was created")
+	}
+}
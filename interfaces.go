@@ -2,6 +2,8 @@
 // teaching purposes.
 package csgo
 
+import "fmt"
+
 // TODO: Session 1 - Implement the Relationer and ColumnStorer interface by using e.g. the
 // Relation and ColumnStore struct (i.e. all method signatures/heads in a separte file). Implement
 // Load, Scan, Select, Print, GetRawData, CreateRelation and GetRelation.
@@ -27,6 +29,25 @@ const (
 	GT Comparison = ">"
 	// GEQ is the "greater equal than" comparison operation.
 	GEQ Comparison = ">="
+	// IN is the "element of" comparison operation, the comparison value is a slice of values.
+	IN Comparison = "in"
+)
+
+// Predicate is a single selection predicate (Col Comp Value), see Select.
+type Predicate struct {
+	Col   AttrInfo
+	Comp  Comparison
+	Value interface{}
+}
+
+// LogicalOp defines how the results of several predicates are combined.
+type LogicalOp int
+
+const (
+	// AND selects the rows satisfying all predicates.
+	AND LogicalOp = iota
+	// OR selects the rows satisfying at least one predicate.
+	OR
 )
 
 // Compression is an enumeration type for all supported column encoding methods.
@@ -41,8 +62,59 @@ const (
 	DICT
 	// FOR is the frame of reference encoding method.
 	FOR
+	// DELTA stores the differences between consecutive values (for increasing INT columns).
+	DELTA
+	// BITPACK stores every value using the minimum bit width (for INT columns with small values).
+	BITPACK
+	// XOR stores the XOR of consecutive values without leading and trailing zeros (for FLOAT
+	// columns).
+	XOR
+	// FRONTCODING stores the prefix shared with the previous value only once (for sorted or similar
+	// STRING columns).
+	FRONTCODING
+	// STRINGHEAP stores all values of a STRING column in a single byte buffer.
+	STRINGHEAP
+)
+
+const (
+	// DICTRLE is dictionary encoding with run-length encoded codes.
+	DICTRLE = DICT | RLE<<8
+	// DICTFOR is dictionary encoding with frame of reference encoded codes.
+	DICTFOR = DICT | FOR<<8
 )
 
+// Layered returns the encoding applying enc to the values and inner to the data produced by enc
+// (e.g. DICT.Layered(RLE) run-length encodes the codes of a dictionary, see DICTRLE).
+func (enc Compression) Layered(inner Compression) Compression {
+	return enc.Outer() | inner<<8
+}
+
+// Outer returns the encoding applied to the values.
+func (enc Compression) Outer() Compression {
+	return enc & 0xff
+}
+
+// Inner returns the encoding applied to the data produced by the outer encoding (NOCOMP if the
+// encoding isn't layered).
+func (enc Compression) Inner() Compression {
+	return enc >> 8
+}
+
+// String returns the name of the encoding (e.g. "DICT+RLE" for DICTRLE).
+func (enc Compression) String() string {
+	names := map[Compression]string{NOCOMP: "NOCOMP", RLE: "RLE", DICT: "DICT", FOR: "FOR", DELTA: "DELTA", BITPACK: "BITPACK", XOR: "XOR",
+		FRONTCODING: "FRONTCODING", STRINGHEAP: "STRINGHEAP"}
+
+	name, found := names[enc.Outer()]
+	if !found {
+		name = fmt.Sprintf("Compression(%d)", int(enc.Outer()))
+	}
+	if enc.Inner() != NOCOMP {
+		name += "+" + enc.Inner().String()
+	}
+	return name
+}
+
 // JoinType defines all supported types of join.
 type JoinType int
 
@@ -97,9 +169,28 @@ const (
 	GROUPED = 0x02
 	// NULLGROUP is the combination of NULLABLE and GROUPED
 	NULLGROUP = NULLABLE | GROUPED
-	// Space for another six flags (e.g. index/primary key/search structures)
+	// HASHINDEX means the column keeps a hash index for equality predicates (see HashIndex)
+	HASHINDEX = 0x04
+	// ORDEREDINDEX means the column keeps an ordered index for range predicates (see OrderedIndex)
+	ORDEREDINDEX = 0x08
+	// BITMAPINDEX means the column keeps a bitmap per distinct value (see BitmapIndex)
+	BITMAPINDEX = 0x10
+	// PRIMARYKEY means the column is part of the primary key of the relation (see
+	// Relation.LookupByKey). The key can span several columns, so it is only enforced by
+	// Relation.AddRow, not by Column.AddRow
+	PRIMARYKEY = 0x20
+	// UNIQUE means the values of the column have to be distinct (enforced by Column.AddRow and
+	// Column.AddRows)
+	UNIQUE = 0x40
+	// SORTED means the values of the column are in ascending order (set by MergeSort)
+	SORTED = 0x80
 )
 
+// dataFlags returns the flags which are relevant for storing the values (see DataStore.GetFlags).
+func (flags ColumnFlags) dataFlags() ColumnFlags {
+	return flags & NULLGROUP
+}
+
 // AttrInfo contains meta information about a column (name and type).
 type AttrInfo struct {
 	// Name is the name of the column.
@@ -112,12 +203,25 @@ type AttrInfo struct {
 	Flags ColumnFlags
 }
 
+// matches reports whether both signatures describe the same column. The encoding and all flags
+// except the data flags (see dataFlags) are ignored, so columns reencoded by Reencode, indexed by
+// CreateIndex or sorted by MergeSort can still be referenced by their original signature.
+func (sig AttrInfo) matches(other AttrInfo) bool {
+	sig.Enc, other.Enc = NOCOMP, NOCOMP
+	sig.Flags, other.Flags = sig.Flags.dataFlags(), other.Flags.dataFlags()
+	return sig == other
+}
+
 // Column is a single column containing the signature and the payload.
 type Column struct {
 	// Signature gives meta information about the column.
 	Signature AttrInfo
 	// Data contains the raw or compressed data (e.g. in the form of a slice).
 	Data interface{}
+	// Zones contains the min/max metadata of the row blocks (nil for grouped columns and views).
+	Zones *ZoneMap
+	// Indexes contains the search structures declared by the column flags (see Index).
+	Indexes []Index
 }
 
 // Relation is an example structure on which one could define the Relationer methods.
@@ -170,6 +274,8 @@ type Relationer interface {
 type ColumnStore struct {
 	// Relations is the mapping of relation names to their object reference.
 	Relations map[string]Relationer
+	// ForeignKeys contains the declared references between the relations (see DeclareForeignKey).
+	ForeignKeys []ForeignKey
 }
 
 // ColumnStorer is an interface for an In-Memory Column Store (the database).
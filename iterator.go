@@ -0,0 +1,74 @@
+package csgo
+
+import "errors"
+
+// Iterator reads the values of a DataStore sequentially. The values are fetched in chunks of
+// BatchSize rows via GetRange, so T has to match the slice type returned by GetRange (e.g. int for
+// INT data or []int for grouped INT data).
+//
+//	for it := NewIterator[int](ds); it.Next(); {
+//		value := it.Value()
+//	}
+type Iterator[T any] struct {
+	ds       DataStore
+	chunk    []T
+	chunkPos int
+	nextRow  int
+	err      error
+}
+
+// NewIterator creates an Iterator positioned before the first row of ds.
+func NewIterator[T any](ds DataStore) *Iterator[T] {
+	return &Iterator[T]{ds: ds, chunkPos: -1}
+}
+
+// Next advances the iterator to the next row. It returns false after the last row or if an error
+// occurred (see Err).
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.chunkPos++
+	if it.chunkPos < len(it.chunk) {
+		return true
+	}
+
+	numRows := it.ds.GetNumRows()
+	if it.nextRow >= numRows {
+		return false
+	}
+
+	end := min(it.nextRow+BatchSize, numRows)
+	values, err := it.ds.GetRange(it.nextRow, end)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	chunk, rightType := values.([]T)
+	if !rightType {
+		it.err = errors.New("type mismatch")
+		return false
+	}
+
+	it.chunk = chunk
+	it.chunkPos = 0
+	it.nextRow = end
+	return true
+}
+
+// Value returns the value of the current row.
+func (it *Iterator[T]) Value() T {
+	return it.chunk[it.chunkPos]
+}
+
+// Row returns the index of the current row.
+func (it *Iterator[T]) Row() int {
+	return it.nextRow - len(it.chunk) + it.chunkPos
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,35 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	values := make([]int, 2*BatchSize+3)
+	for i := range values {
+		values[i] = i / 7
+	}
+
+	for _, enc := range []Compression{NOCOMP, RLE, DICT} {
+		col := NewColumnWithData(AttrInfo{"col", INT, enc, 0}, values)
+
+		output := []int{}
+		it := NewIterator[int](col.Data.(DataStore))
+		for it.Next() {
+			if it.Row() != len(output) {
+				t.Errorf("encoding %v: expected row %d, got %d", enc, len(output), it.Row())
+			}
+			output = append(output, it.Value())
+		}
+
+		if it.Err() != nil || !reflect.DeepEqual(output, values) {
+			t.Errorf("encoding %v: iteration failed (%v)", enc, it.Err())
+		}
+	}
+
+	it := NewIterator[string](NewColumnWithData(AttrInfo{"col", INT, NOCOMP, 0}, []int{1}).Data.(DataStore))
+	if it.Next() || it.Err() == nil {
+		t.Error("iterating with a mismatching type succeeded")
+	}
+}
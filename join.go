@@ -0,0 +1,407 @@
+package csgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// JoinAlgorithm is an enumeration type for all physical join operators.
+type JoinAlgorithm int
+
+const (
+	// HASHJOIN uses the (parallel) hash join operator (see Relation.HashJoin).
+	HASHJOIN JoinAlgorithm = iota
+	// MERGEJOIN uses the sort merge join operator (see Relation.MergeJoin).
+	MERGEJOIN
+	// NESTEDLOOPJOIN compares every pair of rows (see Relation.NestedLoopJoin).
+	NESTEDLOOPJOIN
+)
+
+// String returns the name of the join algorithm.
+func (algorithm JoinAlgorithm) String() string {
+	switch algorithm {
+	case HASHJOIN:
+		return "HashJoin"
+	case MERGEJOIN:
+		return "MergeJoin"
+	case NESTEDLOOPJOIN:
+		return "NestedLoopJoin"
+	}
+	return fmt.Sprintf("JoinAlgorithm(%d)", int(algorithm))
+}
+
+// estimated costs per processed element, in abstract units
+const (
+	costHashBuild   = 4.0
+	costHashProbe   = 2.0
+	costSortCompare = 1.5
+	costMergeStep   = 1.0
+	costPairCompare = 1.0
+	costOutputRow   = 1.0
+)
+
+// joinSampleSize is the maximum number of rows inspected to estimate the key cardinality.
+const joinSampleSize = 1024
+
+// JoinPlan describes the decision of the join optimizer (see Relation.PlanJoin).
+type JoinPlan struct {
+	// Algorithm is the chosen join operator.
+	Algorithm JoinAlgorithm
+	// LeftRows and RightRows are the input sizes.
+	LeftRows, RightRows int
+	// LeftDistinct and RightDistinct are the estimated numbers of distinct join keys.
+	LeftDistinct, RightDistinct int
+	// LeftSorted and RightSorted report whether the inputs are already ordered by their join keys.
+	LeftSorted, RightSorted bool
+	// EstimatedRows is the estimated number of output rows.
+	EstimatedRows int
+	// Costs contains the estimated costs of all applicable join operators.
+	Costs map[JoinAlgorithm]float64
+}
+
+// String returns a human readable description of the plan.
+func (plan JoinPlan) String() string {
+	desc := fmt.Sprintf("%v (left: %d rows, ~%d keys, sorted: %v; right: %d rows, ~%d keys, sorted: %v; ~%d output rows)",
+		plan.Algorithm, plan.LeftRows, plan.LeftDistinct, plan.LeftSorted, plan.RightRows, plan.RightDistinct, plan.RightSorted, plan.EstimatedRows)
+
+	for _, algorithm := range []JoinAlgorithm{HASHJOIN, MERGEJOIN, NESTEDLOOPJOIN} {
+		if cost, found := plan.Costs[algorithm]; found {
+			desc += fmt.Sprintf("\n  %v: cost %.0f", algorithm, cost)
+		}
+	}
+	return desc
+}
+
+// findJoinColumns returns the columns of rel matching sigs (in order of sigs).
+func findJoinColumns(rel *Relation, sigs []AttrInfo) []*Column {
+	cols := make([]*Column, len(sigs))
+
+	for sigIndex, colSig := range sigs {
+		for colIndex, col := range rel.Columns {
+			if col.Signature.matches(colSig) {
+				cols[sigIndex] = &rel.Columns[colIndex]
+				break
+			}
+		}
+
+		if cols[sigIndex] == nil {
+			panic("column not found")
+		}
+	}
+
+	return cols
+}
+
+// joinOutputColumns creates the output columns for all columns of base, referencing the given
+// rows. The names of the output columns are prefixed with tableName (e.g. "PART.PARTKEY").
+func joinOutputColumns(base *Relation, tableName string, indices []int) []Column {
+	cols := []Column{}
+	for colIndex, col := range base.Columns {
+		signature := AttrInfo{Name: tableName + "." + col.Signature.Name, Enc: col.Signature.Enc, Type: col.Signature.Type}
+		cols = append(cols, newColumnView(signature, &base.Columns[colIndex], indices))
+	}
+	return cols
+}
+
+// compareKeys compares the join keys of two rows lexicographically and returns -1, 0 or 1.
+func compareKeys(leftKeys []*Column, leftRow int, rightKeys []*Column, rightRow int) int {
+	for keyIndex := range leftKeys {
+		leftValue, _ := leftKeys[keyIndex].GetRow(leftRow)
+		rightValue, _ := rightKeys[keyIndex].GetRow(rightRow)
+		lesser := compFuncs[leftKeys[keyIndex].Signature.Type][LT]
+
+		if lesser(leftValue, rightValue) {
+			return -1
+		}
+		if lesser(rightValue, leftValue) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// matchesComparison returns whether a comparison result (see compareKeys) satisfies compType.
+func matchesComparison(result int, compType Comparison) bool {
+	switch compType {
+	case EQ:
+		return result == 0
+	case NEQ:
+		return result != 0
+	case LT:
+		return result < 0
+	case LEQ:
+		return result <= 0
+	case GT:
+		return result > 0
+	case GEQ:
+		return result >= 0
+	}
+	panic("unknown comparison")
+}
+
+// isSortedBy returns whether the rows are known to be in ascending order of the given key columns.
+// Only a single SORTED key column (see MergeSort) is trusted, the rows aren't read to find out.
+func isSortedBy(keys []*Column) bool {
+	return len(keys) == 1 && keys[0].Signature.Flags&SORTED != 0
+}
+
+// estimateDistinct estimates the number of distinct key tuples. Dictionary encoded single
+// columns report their exact dictionary size, otherwise a sample of the rows is evaluated using the
+// GEE estimator (sqrt(n/sample) * values seen once + values seen more than once).
+// Samples without any duplicates are considered unique keys.
+func estimateDistinct(keys []*Column) int {
+	numRows := keys[0].GetNumRows()
+	if numRows == 0 {
+		return 0
+	}
+
+	if len(keys) == 1 {
+		if dict, isDict := keys[0].Data.(*DictEncodedDataStore); isDict {
+			return dict.numCodes()
+		}
+	}
+
+	sampleSize := numRows
+	if sampleSize > joinSampleSize {
+		sampleSize = joinSampleSize
+	}
+
+	frequencies := map[uint64]int{}
+	for i := 0; i < sampleSize; i++ {
+		row := i * numRows / sampleSize
+		hash := uint64(0)
+		for _, key := range keys {
+			value, _ := key.GetRow(row)
+			hash = hash*31 + hashValue(value)
+		}
+		frequencies[hash]++
+	}
+
+	seenOnce, seenMore := 0, 0
+	for _, frequency := range frequencies {
+		if frequency == 1 {
+			seenOnce++
+		} else {
+			seenMore++
+		}
+	}
+
+	if seenMore == 0 {
+		// no duplicates at all, the key is most likely unique
+		return numRows
+	}
+
+	estimate := int(math.Sqrt(float64(numRows)/float64(sampleSize))*float64(seenOnce)) + seenMore
+	if estimate > numRows {
+		estimate = numRows
+	}
+	return estimate
+}
+
+// PlanJoin estimates the input sizes, key cardinalities and existing sort orders of both
+// relations and chooses the cheapest join operator supporting joinType and compType.
+func (r Relation) PlanJoin(leftCols []AttrInfo, rightRelation Relationer, rightCols []AttrInfo, joinType JoinType, compType Comparison) JoinPlan {
+	right, isRelation := rightRelation.(Relation)
+
+	if !isRelation {
+		panic("unknown relation type")
+	}
+
+	if len(leftCols) == 0 || len(leftCols) != len(rightCols) {
+		panic("join requires the same number of join columns on both sides")
+	}
+
+	if joinType != INNER && joinType != SEMI {
+		panic("NULL values not implemented")
+	}
+
+	leftKeys := findJoinColumns(&r, leftCols)
+	rightKeys := findJoinColumns(&right, rightCols)
+
+	plan := JoinPlan{
+		LeftRows:      leftKeys[0].GetNumRows(),
+		RightRows:     rightKeys[0].GetNumRows(),
+		LeftDistinct:  estimateDistinct(leftKeys),
+		RightDistinct: estimateDistinct(rightKeys),
+		LeftSorted:    isSortedBy(leftKeys),
+		RightSorted:   isSortedBy(rightKeys),
+		Costs:         map[JoinAlgorithm]float64{},
+	}
+
+	leftRows, rightRows := float64(plan.LeftRows), float64(plan.RightRows)
+	pairs := leftRows * rightRows
+
+	// selectivity of the join predicate
+	maxDistinct := math.Max(1, math.Max(float64(plan.LeftDistinct), float64(plan.RightDistinct)))
+	var outputRows float64
+	switch compType {
+	case EQ:
+		outputRows = pairs / maxDistinct
+	case NEQ:
+		outputRows = pairs - pairs/maxDistinct
+	default:
+		outputRows = pairs / 2
+	}
+	if joinType == SEMI {
+		outputRows = math.Min(outputRows, leftRows)
+	}
+	plan.EstimatedRows = int(outputRows)
+
+	sortCost := func(rows float64, sorted bool) float64 {
+		if sorted || rows < 2 {
+			return 0
+		}
+		return rows * math.Log2(rows) * costSortCompare
+	}
+
+	// hash join: equality only, the smaller input gets used as build side
+	if compType == EQ {
+		build, probe := math.Min(leftRows, rightRows), math.Max(leftRows, rightRows)
+		if joinType == SEMI {
+			build, probe = rightRows, leftRows
+		}
+		plan.Costs[HASHJOIN] = build*costHashBuild + probe*costHashProbe + outputRows*costOutputRow
+	}
+
+	// merge join: equality only, other comparisons don't follow the merge order
+	if compType == EQ {
+		mergeCost := sortCost(leftRows, plan.LeftSorted) + sortCost(rightRows, plan.RightSorted) + (leftRows+rightRows)*costMergeStep
+		plan.Costs[MERGEJOIN] = mergeCost + outputRows*costOutputRow
+	}
+
+	// nested loop join: supports everything
+	plan.Costs[NESTEDLOOPJOIN] = pairs*costPairCompare + outputRows*costOutputRow
+
+	plan.Algorithm = NESTEDLOOPJOIN
+	for _, algorithm := range []JoinAlgorithm{HASHJOIN, MERGEJOIN} {
+		if cost, found := plan.Costs[algorithm]; found && cost < plan.Costs[plan.Algorithm] {
+			plan.Algorithm = algorithm
+		}
+	}
+
+	return plan
+}
+
+// Join executes a join between two relations using the join operator chosen by PlanJoin.
+// The returned JoinPlan reports the decision.
+func (r Relation) Join(leftCols []AttrInfo, rightRelation Relationer, rightCols []AttrInfo, joinType JoinType, compType Comparison) (Relationer, JoinPlan) {
+	plan := r.PlanJoin(leftCols, rightRelation, rightCols, joinType, compType)
+
+	switch plan.Algorithm {
+	case HASHJOIN:
+		return r.HashJoin(leftCols, rightRelation, rightCols, joinType, compType), plan
+	case MERGEJOIN:
+		return r.MergeJoin(leftCols, rightRelation, rightCols, joinType, compType), plan
+	default:
+		return r.NestedLoopJoin(leftCols, rightRelation, rightCols, joinType, compType), plan
+	}
+}
+
+// NestedLoopJoin implements the nested loop join operator between two relations.
+// It supports every comparison type, multiple join columns are compared lexicographically.
+// The output is ordered by the rows of the left relation.
+func (r Relation) NestedLoopJoin(leftCols []AttrInfo, rightRelation Relationer, rightCols []AttrInfo, joinType JoinType, compType Comparison) Relationer {
+	right, isRelation := rightRelation.(Relation)
+
+	if !isRelation {
+		panic("unknown relation type")
+	}
+
+	if len(leftCols) == 0 || len(leftCols) != len(rightCols) {
+		panic("join requires the same number of join columns on both sides")
+	}
+
+	left := &r
+	leftKeys := findJoinColumns(left, leftCols)
+	rightKeys := findJoinColumns(&right, rightCols)
+	maxLeftRows := leftKeys[0].GetNumRows()
+	maxRightRows := rightKeys[0].GetNumRows()
+
+	output := Relation{Name: r.Name + " x " + right.Name, Columns: []Column{}}
+	leftIndices := []int{}
+	rightIndices := []int{}
+
+	innerJoin := func() {
+		for leftRow := 0; leftRow < maxLeftRows; leftRow++ {
+			for rightRow := 0; rightRow < maxRightRows; rightRow++ {
+				if matchesComparison(compareKeys(leftKeys, leftRow, rightKeys, rightRow), compType) {
+					leftIndices = append(leftIndices, leftRow)
+					rightIndices = append(rightIndices, rightRow)
+				}
+			}
+		}
+	}
+
+	semiJoin := func() {
+		for leftRow := 0; leftRow < maxLeftRows; leftRow++ {
+			for rightRow := 0; rightRow < maxRightRows; rightRow++ {
+				if matchesComparison(compareKeys(leftKeys, leftRow, rightKeys, rightRow), compType) {
+					leftIndices = append(leftIndices, leftRow)
+					break
+				}
+			}
+		}
+	}
+
+	switch joinType {
+	case INNER:
+		innerJoin()
+		output.Columns = append(output.Columns, joinOutputColumns(left, left.Name, leftIndices)...)
+		output.Columns = append(output.Columns, joinOutputColumns(&right, right.Name, rightIndices)...)
+	case SEMI:
+		output.Name = left.Name + " (x " + right.Name + ")"
+		semiJoin()
+		output.Columns = append(output.Columns, joinOutputColumns(left, left.Name, leftIndices)...)
+	case RIGHTOUTER:
+		panic("NULL values not implemented")
+	case LEFTOUTER:
+		panic("NULL values not implemented")
+	default:
+		panic("unknown join type")
+	}
+
+	return output
+}
+
+// CrossJoin returns the cartesian product of two relations.
+// The output is ordered by the rows of the left relation, the names of the output columns are
+// prefixed with the name of their relation.
+// maxRows guards against accidentally huge products: if the product would contain more than
+// maxRows rows, CrossJoin fails before producing any output. A maxRows value <= 0 disables the
+// guard.
+func (r Relation) CrossJoin(rightRelation Relationer, maxRows int) Relationer {
+	right, isRelation := rightRelation.(Relation)
+
+	if !isRelation {
+		panic("unknown relation type")
+	}
+
+	maxLeftRows, maxRightRows := 0, 0
+	if len(r.Columns) > 0 {
+		maxLeftRows = r.Columns[0].GetNumRows()
+	}
+	if len(right.Columns) > 0 {
+		maxRightRows = right.Columns[0].GetNumRows()
+	}
+
+	if maxRows > 0 && maxLeftRows > 0 && maxRightRows > maxRows/maxLeftRows {
+		panic(fmt.Sprintf("cross join of %s (%d rows) and %s (%d rows) exceeds the limit of %d rows", r.Name, maxLeftRows, right.Name, maxRightRows, maxRows))
+	}
+
+	output := Relation{Name: r.Name + " x " + right.Name, Columns: []Column{}}
+
+	leftIndices := make([]int, 0, maxLeftRows*maxRightRows)
+	rightIndices := make([]int, 0, maxLeftRows*maxRightRows)
+
+	for leftRow := 0; leftRow < maxLeftRows; leftRow++ {
+		for rightRow := 0; rightRow < maxRightRows; rightRow++ {
+			leftIndices = append(leftIndices, leftRow)
+			rightIndices = append(rightIndices, rightRow)
+		}
+	}
+
+	output.Columns = append(output.Columns, joinOutputColumns(&r, r.Name, leftIndices)...)
+	output.Columns = append(output.Columns, joinOutputColumns(&right, right.Name, rightIndices)...)
+
+	return output
+}
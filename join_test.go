@@ -0,0 +1,170 @@
+package csgo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRelationNestedLoopJoin(t *testing.T) {
+	left := Relation{Name: "left", Columns: []Column{NewColumnWithData(AttrInfo{"leftCol1", INT, NOCOMP, 0}, []int{1, 2, 3})}}
+	right := Relation{Name: "right", Columns: []Column{NewColumnWithData(AttrInfo{"rightCol1", INT, NOCOMP, 0}, []int{2, 3, 4})}}
+	leftCols := []AttrInfo{{"leftCol1", INT, NOCOMP, 0}}
+	rightCols := []AttrInfo{{"rightCol1", INT, NOCOMP, 0}}
+
+	cases := []struct {
+		joinType JoinType
+		compType Comparison
+		output   Relation
+	}{
+		{joinType: INNER, compType: EQ, output: Relation{Name: "left x right", Columns: []Column{
+			NewColumnWithData(AttrInfo{"left.leftCol1", INT, NOCOMP, 0}, []int{2, 3}),
+			NewColumnWithData(AttrInfo{"right.rightCol1", INT, NOCOMP, 0}, []int{2, 3}),
+		}}},
+		{joinType: INNER, compType: LT, output: Relation{Name: "left x right", Columns: []Column{
+			NewColumnWithData(AttrInfo{"left.leftCol1", INT, NOCOMP, 0}, []int{1, 1, 1, 2, 2, 3}),
+			NewColumnWithData(AttrInfo{"right.rightCol1", INT, NOCOMP, 0}, []int{2, 3, 4, 3, 4, 4}),
+		}}},
+		{joinType: INNER, compType: NEQ, output: Relation{Name: "left x right", Columns: []Column{
+			NewColumnWithData(AttrInfo{"left.leftCol1", INT, NOCOMP, 0}, []int{1, 1, 1, 2, 2, 3, 3}),
+			NewColumnWithData(AttrInfo{"right.rightCol1", INT, NOCOMP, 0}, []int{2, 3, 4, 3, 4, 2, 4}),
+		}}},
+		{joinType: SEMI, compType: GT, output: Relation{Name: "left (x right)", Columns: []Column{
+			NewColumnWithData(AttrInfo{"left.leftCol1", INT, NOCOMP, 0}, []int{3}),
+		}}},
+	}
+
+	for testCaseID, testCase := range cases {
+		output := left.NestedLoopJoin(leftCols, right, rightCols, testCase.joinType, testCase.compType).(Relation).Materialize()
+
+		if !reflect.DeepEqual(output, testCase.output) {
+			t.Errorf("test case %d failed", testCaseID)
+			output.Print()
+		}
+	}
+}
+
+func TestRelationPlanJoin(t *testing.T) {
+	createRelation := func(name string, numRows int, step int) Relation {
+		keys := make([]int, numRows)
+		for i := range keys {
+			keys[i] = (i * step) % numRows
+		}
+		return Relation{Name: name, Columns: []Column{NewColumnWithData(AttrInfo{"key", INT, NOCOMP, 0}, keys)}}
+	}
+
+	cols := []AttrInfo{{"key", INT, NOCOMP, 0}}
+	tiny := createRelation("tiny", 3, 2)
+	unsorted := createRelation("unsorted", 5000, 7)
+	// only SORTED key columns are considered sorted, the rows themselves aren't checked
+	sorted := createRelation("sorted", 5000, 1).MergeSort(cols, ASC).(Relation)
+
+	cases := []struct {
+		left      Relation
+		right     Relation
+		joinType  JoinType
+		compType  Comparison
+		algorithm JoinAlgorithm
+	}{
+		{left: tiny, right: tiny, joinType: INNER, compType: EQ, algorithm: NESTEDLOOPJOIN},
+		{left: unsorted, right: unsorted, joinType: INNER, compType: EQ, algorithm: HASHJOIN},
+		{left: sorted, right: sorted, joinType: INNER, compType: EQ, algorithm: MERGEJOIN},
+		{left: unsorted, right: sorted, joinType: INNER, compType: LT, algorithm: NESTEDLOOPJOIN},
+		{left: unsorted, right: sorted, joinType: SEMI, compType: LT, algorithm: NESTEDLOOPJOIN},
+	}
+
+	for testCaseID, testCase := range cases {
+		plan := testCase.left.PlanJoin(cols, testCase.right, cols, testCase.joinType, testCase.compType)
+
+		if plan.Algorithm != testCase.algorithm {
+			t.Errorf("test case %d: expected %v, got %v", testCaseID, testCase.algorithm, plan)
+		}
+	}
+
+	plan := unsorted.PlanJoin(cols, sorted, cols, INNER, EQ)
+	if plan.LeftSorted || !plan.RightSorted || plan.LeftRows != 5000 || plan.LeftDistinct != 5000 {
+		t.Errorf("unexpected statistics: %v", plan)
+	}
+	if plan := unsorted.PlanJoin(cols, createRelation("ordered", 5000, 1), cols, INNER, EQ); plan.RightSorted {
+		t.Errorf("input without SORTED key column was considered sorted: %v", plan)
+	}
+
+	output, plan := tiny.Join(cols, tiny, cols, INNER, EQ)
+	data, _ := output.GetRawData()
+	if plan.Algorithm != NESTEDLOOPJOIN || !reflect.DeepEqual(data, []interface{}{[]int{0, 2, 1}, []int{0, 2, 1}}) {
+		t.Errorf("unexpected join output %v (%v)", data, plan)
+	}
+}
+
+func TestRelationJoinComparisons(t *testing.T) {
+	createRelation := func(name string, numRows int, step int) Relation {
+		keys := make([]int, numRows)
+		for i := range keys {
+			keys[i] = (i * step) % 50
+		}
+		return Relation{Name: name, Columns: []Column{NewColumnWithData(AttrInfo{"key", INT, NOCOMP, 0}, keys)}}
+	}
+
+	// returns the output rows in sorted order, the join operators don't agree on the row order
+	sortedRows := func(output Relationer) [][2]int {
+		data, _ := output.GetRawData()
+		rows := make([][2]int, len(data[0].([]int)))
+		for row := range rows {
+			for col := range data {
+				rows[row][col] = data[col].([]int)[row]
+			}
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			return rows[i][0] < rows[j][0] || (rows[i][0] == rows[j][0] && rows[i][1] < rows[j][1])
+		})
+		return rows
+	}
+
+	cols := []AttrInfo{{"key", INT, NOCOMP, 0}}
+	inputs := []Relation{createRelation("sorted", 200, 1), createRelation("unsorted", 300, 7)}
+
+	for _, joinType := range []JoinType{INNER, SEMI} {
+		for _, compType := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+			for _, left := range inputs {
+				for _, right := range inputs {
+					output, plan := left.Join(cols, right, cols, joinType, compType)
+					expected := left.NestedLoopJoin(cols, right, cols, joinType, compType)
+
+					if !reflect.DeepEqual(sortedRows(output), sortedRows(expected)) {
+						t.Errorf("%v join of %s and %s on %s using %v differs from nested loop join", joinType, left.Name, right.Name, compType, plan.Algorithm)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRelationCrossJoin(t *testing.T) {
+	left := Relation{Name: "left", Columns: []Column{NewColumnWithData(AttrInfo{"leftCol1", INT, NOCOMP, 0}, []int{1, 2})}}
+	right := Relation{Name: "right", Columns: []Column{
+		NewColumnWithData(AttrInfo{"rightCol1", STRING, NOCOMP, 0}, []string{"a", "b", "c"}),
+		NewColumnWithData(AttrInfo{"rightCol2", FLOAT, NOCOMP, 0}, []float64{0.1, 0.2, 0.3}),
+	}}
+
+	expected := Relation{Name: "left x right", Columns: []Column{
+		NewColumnWithData(AttrInfo{"left.leftCol1", INT, NOCOMP, 0}, []int{1, 1, 1, 2, 2, 2}),
+		NewColumnWithData(AttrInfo{"right.rightCol1", STRING, NOCOMP, 0}, []string{"a", "b", "c", "a", "b", "c"}),
+		NewColumnWithData(AttrInfo{"right.rightCol2", FLOAT, NOCOMP, 0}, []float64{0.1, 0.2, 0.3, 0.1, 0.2, 0.3}),
+	}}
+
+	for _, maxRows := range []int{0, 6, 100} {
+		output := left.CrossJoin(right, maxRows).(Relation).Materialize()
+
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("cross join with limit %d failed", maxRows)
+			output.Print()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("cross join exceeding the row limit succeeded")
+		}
+	}()
+	left.CrossJoin(right, 5)
+}
@@ -0,0 +1,128 @@
+package csgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// keyColumns returns the columns forming the primary key of the relation (all columns flagged
+// PRIMARYKEY in column order).
+func (r Relation) keyColumns() []*Column {
+	keyCols := []*Column{}
+	for colIndex := range r.Columns {
+		if r.Columns[colIndex].Signature.Flags&PRIMARYKEY != 0 {
+			keyCols = append(keyCols, &r.Columns[colIndex])
+		}
+	}
+	return keyCols
+}
+
+// findKey returns the index of the row whose key columns contain the given values or -1. The
+// candidate rows are looked up in the hash index of the first key column, so only rows sharing
+// the first value need to be compared.
+func findKey(keyCols []*Column, values []interface{}) (int, error) {
+	candidates, err := keyCols[0].selectRows(EQ, values[0], 1)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, row := range candidates {
+		matches := true
+		for keyIndex, col := range keyCols[1:] {
+			value, err := col.GetRow(row)
+			if err != nil {
+				return -1, err
+			}
+			matches = matches && value == values[keyIndex+1]
+		}
+
+		if matches {
+			return row, nil
+		}
+	}
+	return -1, nil
+}
+
+// AddRow appends a row with the given values (one per column) to the relation. The row is
+// rejected if a value doesn't match the type of its column, if it violates the SORTED or UNIQUE
+// constraint of its column or if the primary key (see PRIMARYKEY) already exists. All checks run
+// before the first column is changed, so a rejected row leaves the relation unchanged.
+func (r Relation) AddRow(values ...interface{}) error {
+	if len(values) != len(r.Columns) {
+		return fmt.Errorf("expected %d values, got %d", len(r.Columns), len(values))
+	}
+
+	keyValues := []interface{}{}
+	for colIndex := range r.Columns {
+		col := &r.Columns[colIndex]
+
+		if col.Signature.Flags&GROUPED != 0 {
+			if !hasGroupedType(col.Signature.Type, values[colIndex]) {
+				return fmt.Errorf("type mismatch in column %s: %#v", col.Signature.Name, values[colIndex])
+			}
+			continue
+		}
+		if !hasType(col.Signature.Type, values[colIndex]) {
+			return fmt.Errorf("type mismatch in column %s: %#v", col.Signature.Name, values[colIndex])
+		}
+
+		if err := col.checkValues([]interface{}{values[colIndex]}); err != nil {
+			return err
+		}
+
+		if col.Signature.Flags&PRIMARYKEY != 0 {
+			keyValues = append(keyValues, values[colIndex])
+		}
+	}
+
+	if keyCols := r.keyColumns(); len(keyCols) > 0 {
+		row, err := findKey(keyCols, keyValues)
+		if err != nil {
+			return err
+		}
+		if row >= 0 {
+			return fmt.Errorf("duplicate primary key %v (row %d)", keyValues, row)
+		}
+	}
+
+	for colIndex := range r.Columns {
+		if _, err := r.Columns[colIndex].appendRow(r.Columns[colIndex].Signature.Type, values[colIndex]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LookupByKey returns the values of the row with the given primary key (one value per PRIMARYKEY
+// column in column order). The row is found using the hash index of the key columns.
+func (r Relation) LookupByKey(values ...interface{}) ([]interface{}, error) {
+	keyCols := r.keyColumns()
+	if len(keyCols) == 0 {
+		return nil, errors.New("relation has no primary key")
+	}
+	if len(values) != len(keyCols) {
+		return nil, fmt.Errorf("expected %d key values, got %d", len(keyCols), len(values))
+	}
+
+	for keyIndex, col := range keyCols {
+		if !hasType(col.Signature.Type, values[keyIndex]) {
+			return nil, fmt.Errorf("type mismatch in column %s: %#v", col.Signature.Name, values[keyIndex])
+		}
+	}
+
+	row, err := findKey(keyCols, values)
+	if err != nil {
+		return nil, err
+	}
+	if row < 0 {
+		return nil, fmt.Errorf("key %v not found", values)
+	}
+
+	output := make([]interface{}, len(r.Columns))
+	for colIndex, col := range r.Columns {
+		if output[colIndex], err = col.GetRow(row); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
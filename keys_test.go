@@ -0,0 +1,137 @@
+package csgo
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRelationAddRow(t *testing.T) {
+	r := Relation{Name: "partsupp", Columns: []Column{
+		NewColumn(AttrInfo{"PARTKEY", INT, NOCOMP, PRIMARYKEY}),
+		NewColumn(AttrInfo{"SUPPKEY", INT, RLE, PRIMARYKEY}),
+		NewColumn(AttrInfo{"COMMENT", STRING, NOCOMP, UNIQUE}),
+		NewColumn(AttrInfo{"COST", FLOAT, NOCOMP, 0}),
+	}}
+
+	cases := []struct {
+		values []interface{}
+		valid  bool
+	}{
+		{values: []interface{}{1, 1, "a", 0.5}, valid: true},
+		{values: []interface{}{1, 2, "b", 0.5}, valid: true},
+		{values: []interface{}{2, 1, "c", 1.5}, valid: true},
+		{values: []interface{}{2, 1, "d", 1.5}, valid: false},
+		{values: []interface{}{3, 1, "a", 1.5}, valid: false},
+		{values: []interface{}{3, 1, "d", 1}, valid: false},
+		{values: []interface{}{3, 1, "d"}, valid: false},
+		{values: []interface{}{3, 1, "d", 2.5}, valid: true},
+	}
+
+	for testCaseID, testCase := range cases {
+		err := r.AddRow(testCase.values...)
+		if (err == nil) != testCase.valid {
+			t.Errorf("test case %d: unexpected result %v", testCaseID, err)
+		}
+	}
+
+	data, _ := r.GetRawData()
+	expected := []interface{}{[]int{1, 1, 2, 3}, []int{1, 2, 1, 1}, []string{"a", "b", "c", "d"}, []float64{0.5, 0.5, 1.5, 2.5}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+func TestRelationAddRowAtomic(t *testing.T) {
+	r := Relation{Name: "rel", Columns: []Column{
+		NewColumn(AttrInfo{"A", INT, NOCOMP, 0}),
+		NewColumn(AttrInfo{"B", INT, NOCOMP, SORTED}),
+		NewColumn(AttrInfo{"C", STRING, NOCOMP, GROUPED}),
+	}}
+
+	cases := []struct {
+		values []interface{}
+		valid  bool
+	}{
+		{values: []interface{}{1, 5, []string{"a"}}, valid: true},
+		{values: []interface{}{2, 4, []string{"b"}}, valid: false},
+		{values: []interface{}{3, 6, "c"}, valid: false},
+		{values: []interface{}{4, 6, []int{1}}, valid: false},
+		{values: []interface{}{5, 6, []string{}}, valid: true},
+	}
+
+	for testCaseID, testCase := range cases {
+		err := r.AddRow(testCase.values...)
+		if (err == nil) != testCase.valid {
+			t.Errorf("test case %d: unexpected result %v", testCaseID, err)
+		}
+	}
+
+	data, _ := r.GetRawData()
+	expected := []interface{}{[]int{1, 5}, []int{5, 6}, [][]string{{"a"}, {}}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+func TestColumnUnique(t *testing.T) {
+	col := NewColumnWithData(AttrInfo{"col", STRING, DICT, UNIQUE}, []string{"a", "b"})
+
+	if _, err := col.AddRow(STRING, "a"); err == nil {
+		t.Error("duplicate value was added")
+	}
+	if _, err := col.AddRows(STRING, []string{"c", "d", "c"}); err == nil {
+		t.Error("values containing a duplicate were added")
+	}
+	if _, err := col.AddRows(STRING, []string{"c", "d"}); err != nil {
+		t.Errorf("adding unique values failed: %v", err)
+	}
+	if data := col.GetRawData(); !reflect.DeepEqual(data, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("column with duplicate values was created")
+		}
+	}()
+	NewColumnWithData(AttrInfo{"col", INT, NOCOMP, UNIQUE}, []int{1, 2, 1})
+}
+
+func TestRelationLookupByKey(t *testing.T) {
+	r := Relation{Name: "part", Columns: []Column{
+		NewColumn(AttrInfo{"NAME", STRING, NOCOMP, 0}),
+		NewColumn(AttrInfo{"PARTKEY", INT, NOCOMP, PRIMARYKEY}),
+	}}
+
+	file, err := os.Create("keys.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("bolt,3\nnut,1\nscrew,3\nwasher,x\nnail,2")
+	file.Close()
+	defer os.Remove("keys.csv")
+
+	// the duplicate key and the malformed key get skipped
+	r.Load("keys.csv", ',')
+	data, _ := r.GetRawData()
+	if !reflect.DeepEqual(data, []interface{}{[]string{"bolt", "nut", "nail"}, []int{3, 1, 2}}) {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	row, err := r.LookupByKey(2)
+	if err != nil || !reflect.DeepEqual(row, []interface{}{"nail", 2}) {
+		t.Errorf("unexpected row %v (%v)", row, err)
+	}
+
+	for _, key := range [][]interface{}{{4}, {"2"}, {2, 3}} {
+		if _, err := r.LookupByKey(key...); err == nil {
+			t.Errorf("lookup of %v succeeded", key)
+		}
+	}
+
+	noKey := Relation{Name: "rel", Columns: []Column{NewColumn(AttrInfo{"col", INT, NOCOMP, 0})}}
+	if _, err := noKey.LookupByKey(1); err == nil {
+		t.Error("lookup without primary key succeeded")
+	}
+}
@@ -0,0 +1,95 @@
+package csgo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// rough memory usage of the Go representations used by the DataStores (64 bit platform)
+const (
+	wordBytes         = 8
+	interfaceBytes    = 2 * wordBytes
+	stringHeaderBytes = 2 * wordBytes
+	sliceHeaderBytes  = 3 * wordBytes
+	// mapEntryBytes is the overhead of a map entry in addition to its key and value
+	mapEntryBytes = 2 * wordBytes
+)
+
+// valueBytes returns the memory used by a single (unboxed) value, including the elements of
+// grouped values.
+func valueBytes(value interface{}) int {
+	switch typed := value.(type) {
+	case string:
+		return stringHeaderBytes + len(typed)
+	case []int:
+		return sliceHeaderBytes + cap(typed)*wordBytes
+	case []float64:
+		return sliceHeaderBytes + cap(typed)*wordBytes
+	case []string:
+		size := sliceHeaderBytes + (cap(typed)-len(typed))*stringHeaderBytes
+		for _, str := range typed {
+			size += valueBytes(str)
+		}
+		return size
+	}
+	return wordBytes
+}
+
+// MemoryUsage returns the estimated number of bytes used by the column (including its zone map
+// and indexes).
+func (col Column) MemoryUsage() int {
+	size := (col.Data.(DataStore)).MemoryUsage() + col.Zones.memoryUsage()
+	for _, index := range col.Indexes {
+		size += index.MemoryUsage()
+	}
+	return size
+}
+
+// MemoryUsage returns the estimated number of bytes used by all columns of the relation. Columns
+// referencing the rows of other columns only count their position lists.
+func (r Relation) MemoryUsage() int {
+	size := 0
+	for _, col := range r.Columns {
+		size += col.MemoryUsage()
+	}
+	return size
+}
+
+// MemoryUsage returns the estimated number of bytes used by all relations of the column store.
+func (c ColumnStore) MemoryUsage() int {
+	size := 0
+	for _, relation := range c.Relations {
+		if r, isRelation := relation.(Relation); isRelation {
+			size += r.MemoryUsage()
+		}
+	}
+	return size
+}
+
+// PrintMemoryUsage outputs the memory usage of every column and relation of the column store to
+// the standard output.
+func (c ColumnStore) PrintMemoryUsage() {
+	names := []string{}
+	for name := range c.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		r, isRelation := c.Relations[name].(Relation)
+		if !isRelation {
+			continue
+		}
+
+		numRows := 0
+		if len(r.Columns) > 0 {
+			numRows = r.Columns[0].GetNumRows()
+		}
+
+		fmt.Printf("%s (%d rows): %d bytes\n", name, numRows, r.MemoryUsage())
+		for _, col := range r.Columns {
+			fmt.Printf("  %-20s %-10v %12d bytes\n", col.Signature.Name, col.Signature.Enc, col.MemoryUsage())
+		}
+	}
+	fmt.Printf("total: %d bytes\n", c.MemoryUsage())
+}
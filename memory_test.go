@@ -0,0 +1,42 @@
+package csgo
+
+import "testing"
+
+func TestMemoryUsage(t *testing.T) {
+	values := make([]string, 10000)
+	for i := range values {
+		values[i] = []string{"Argentina", "Brazil", "Chile"}[i/4000]
+	}
+
+	usage := map[Compression]int{}
+	for _, enc := range []Compression{NOCOMP, RLE, DICT, DICTRLE} {
+		usage[enc] = NewColumnWithData(AttrInfo{"col", STRING, enc, 0}, values).MemoryUsage()
+	}
+
+	// the payload alone needs 16 bytes (string header) + 5 bytes (average length) per value
+	if usage[NOCOMP] < 10000*21 {
+		t.Errorf("uncompressed memory usage %d is too low", usage[NOCOMP])
+	}
+	if usage[RLE] >= usage[DICT] || usage[DICT] >= usage[NOCOMP] || usage[DICTRLE] >= usage[DICT] {
+		t.Errorf("unexpected memory usage %v", usage)
+	}
+
+	r := Relation{Name: "rel", Columns: []Column{
+		NewColumnWithData(AttrInfo{"col", STRING, RLE, 0}, values),
+		NewColumnWithData(AttrInfo{"key", INT, FOR, 0}, make([]int, 10000)),
+	}}
+	// FOR needs a single byte per value plus the spare capacity left by append
+	if r.MemoryUsage() != r.Columns[0].MemoryUsage()+r.Columns[1].MemoryUsage() || r.Columns[1].Data.(DataStore).MemoryUsage() > 15000 {
+		t.Errorf("unexpected relation memory usage %d", r.MemoryUsage())
+	}
+
+	view := r.Limit(0, 100).(Relation)
+	if view.MemoryUsage() > 2*200*wordBytes {
+		t.Errorf("position lists use %d bytes", view.MemoryUsage())
+	}
+
+	c := ColumnStore{Relations: map[string]Relationer{"rel": r, "view": view}}
+	if c.MemoryUsage() != r.MemoryUsage()+view.MemoryUsage() {
+		t.Errorf("unexpected column store memory usage %d", c.MemoryUsage())
+	}
+}
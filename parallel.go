@@ -0,0 +1,69 @@
+package csgo
+
+import (
+	"runtime"
+	"sync"
+)
+
+// NumWorkers is the number of goroutines used by the parallel operators (e.g. HashJoin) if no
+// explicit worker count is given.
+var NumWorkers = runtime.NumCPU()
+
+// rowRange is a half-open interval [Start, End) of row indices.
+type rowRange struct {
+	Start int
+	End   int
+}
+
+// splitRows divides the rows [0, numRows) into at most numParts contiguous ranges of (nearly)
+// equal size. The ranges are returned in ascending order.
+func splitRows(numRows int, numParts int) []rowRange {
+	if numParts < 1 {
+		numParts = 1
+	}
+	if numParts > numRows {
+		numParts = numRows
+	}
+
+	ranges := make([]rowRange, 0, numParts)
+	start := 0
+	for part := 0; part < numParts; part++ {
+		end := start + (numRows-start)/(numParts-part)
+		ranges = append(ranges, rowRange{start, end})
+		start = end
+	}
+	return ranges
+}
+
+// parallelFor calls fn for every task in [0, numTasks) using at most numWorkers goroutines and
+// returns after all calls have finished.
+func parallelFor(numTasks int, numWorkers int, fn func(task int)) {
+	if numWorkers > numTasks {
+		numWorkers = numTasks
+	}
+
+	if numWorkers <= 1 {
+		for task := 0; task < numTasks; task++ {
+			fn(task)
+		}
+		return
+	}
+
+	tasks := make(chan int, numTasks)
+	for task := 0; task < numTasks; task++ {
+		tasks <- task
+	}
+	close(tasks)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for worker := 0; worker < numWorkers; worker++ {
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				fn(task)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,84 @@
+package csgo
+
+import "errors"
+
+// PositionListDataStore is a read-only DataStore referencing a subset of the rows of another
+// DataStore by their row indices. Operators like Select or HashJoin return columns backed by
+// position lists, so values only get fetched when they are actually read (late materialization).
+// Adding rows to such a column materializes it first (see Column.AddRow).
+type PositionListDataStore struct {
+	// Source is the DataStore containing the values.
+	Source DataStore
+	// Positions contains the row indices (within Source) of all rows of this DataStore.
+	Positions []int
+}
+
+// NewPositionListDataStore creates a new PositionListDataStore. If source is a position list
+// itself, the positions get resolved, so the new DataStore directly references the values.
+func NewPositionListDataStore(source DataStore, positions []int) DataStore {
+	if sourceList, isList := source.(*PositionListDataStore); isList {
+		resolved := make([]int, len(positions))
+		for i, position := range positions {
+			resolved[i] = sourceList.Positions[position]
+		}
+		return &PositionListDataStore{Source: sourceList.Source, Positions: resolved}
+	}
+
+	return &PositionListDataStore{Source: source, Positions: positions}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *PositionListDataStore) GetDataType() DataTypes {
+	return ds.Source.GetDataType()
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *PositionListDataStore) GetFlags() ColumnFlags {
+	return ds.Source.GetFlags()
+}
+
+// AddRow always fails, position lists are read-only. Column.AddRow materializes the column instead.
+func (ds *PositionListDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	return -1, errors.New("position lists are read-only, the column needs to be materialized first")
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *PositionListDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= len(ds.Positions) {
+		return nil, errors.New("index out of bounds")
+	}
+	return ds.Source.GetRow(ds.Positions[rowIndex])
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *PositionListDataStore) GetNumRows() int {
+	return len(ds.Positions)
+}
+
+// AddRows always fails, position lists are read-only. Column.AddRows materializes the column instead.
+func (ds *PositionListDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	return ds.AddRow(typ, values)
+}
+
+// GetRange returns the values of the rows [start, end).
+func (ds *PositionListDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, len(ds.Positions)); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, end-start)
+	for i, position := range ds.Positions[start:end] {
+		value, err := ds.Source.GetRow(position)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return unboxValues(ds.Source.GetDataType(), ds.Source.GetFlags(), values)
+}
+
+// MemoryUsage returns the estimated number of bytes used by the position list. The referenced
+// DataStore isn't included.
+func (ds *PositionListDataStore) MemoryUsage() int {
+	return interfaceBytes + sliceHeaderBytes + cap(ds.Positions)*wordBytes
+}
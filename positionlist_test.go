@@ -0,0 +1,100 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPositionListDataStore(t *testing.T) {
+	source := NewColumnWithData(AttrInfo{"col", STRING, RLE, 0}, []string{"a", "a", "b", "c", "c", "c"})
+	view := newColumnView(source.Signature, &source, []int{5, 0, 2})
+	nested := newColumnView(source.Signature, &view, []int{2, 1})
+
+	if nested.Data.(*PositionListDataStore).Source != source.Data.(DataStore) {
+		t.Error("nested position list was not resolved")
+	}
+
+	cases := []struct {
+		col      Column
+		expected []string
+	}{
+		{col: view, expected: []string{"c", "a", "b"}},
+		{col: nested, expected: []string{"b", "a"}},
+	}
+
+	for testCaseID, testCase := range cases {
+		data := testCase.col.GetRawData()
+		if !reflect.DeepEqual(data, testCase.expected) {
+			t.Errorf("test case %d: expected %v, got %v", testCaseID, testCase.expected, data)
+		}
+
+		materialized := testCase.col.Materialize()
+		if _, isRLE := materialized.Data.(*RLEDataStore); !isRLE || !reflect.DeepEqual(materialized, NewColumnWithData(source.Signature, testCase.expected)) {
+			t.Errorf("test case %d: materialization failed", testCaseID)
+		}
+	}
+
+	if _, err := view.GetRow(3); err == nil {
+		t.Error("out of bounds access succeeded")
+	}
+	if _, err := view.Data.(DataStore).AddRow(STRING, "d"); err == nil {
+		t.Error("adding a row to a position list succeeded")
+	}
+
+	// writing to the column materializes it and leaves the source unchanged
+	if _, err := view.AddRow(STRING, "d"); err != nil {
+		t.Errorf("adding a row to a view failed: %v", err)
+	}
+	if !reflect.DeepEqual(view, NewColumnWithData(source.Signature, []string{"c", "a", "b", "d"})) {
+		t.Error("view was not materialized")
+	}
+	if !reflect.DeepEqual(source.GetRawData(), []string{"a", "a", "b", "c", "c", "c"}) {
+		t.Error("source column was changed")
+	}
+
+	// views which can't be materialized are reported instead of being truncated
+	broken := newColumnView(source.Signature, &source, []int{0, 9})
+	if _, err := broken.AddRow(STRING, "d"); err == nil || broken.GetNumRows() != 2 {
+		t.Error("adding a row to an unreadable view succeeded")
+	}
+	if _, err := broken.AddRows(STRING, []string{"d"}); err == nil || broken.GetNumRows() != 2 {
+		t.Error("adding rows to an unreadable view succeeded")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("materializing an unreadable view succeeded")
+		}
+	}()
+	broken.Materialize()
+}
+
+func TestRelationOperatorResultsWritable(t *testing.T) {
+	input := Relation{Name: "rel", Columns: []Column{
+		NewColumnWithData(AttrInfo{"id", INT, NOCOMP, 0}, []int{3, 1, 2}),
+		NewColumnWithData(AttrInfo{"name", STRING, DICT, 0}, []string{"c", "a", "b"}),
+	}}
+	cols := []AttrInfo{{"id", INT, NOCOMP, 0}}
+
+	results := []Relationer{
+		input.Select(cols[0], GT, 1),
+		input.Limit(1, 2),
+		input.MergeSort(cols, ASC),
+	}
+
+	for resultID, result := range results {
+		output := result.(Relation)
+		numRows := output.Columns[0].GetNumRows()
+
+		if err := output.AddRow(4, "d"); err != nil {
+			t.Errorf("result %d: adding a row failed: %v", resultID, err)
+			continue
+		}
+		if row, _ := output.Columns[1].GetRow(numRows); output.Columns[0].GetNumRows() != numRows+1 || row != "d" {
+			t.Errorf("result %d: row was not added", resultID)
+		}
+	}
+
+	if data, _ := input.GetRawData(); !reflect.DeepEqual(data, []interface{}{[]int{3, 1, 2}, []string{"c", "a", "b"}}) {
+		t.Errorf("input relation was changed: %v", data)
+	}
+}
@@ -1,6 +1,7 @@
 package csgo
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -17,7 +18,7 @@ func (r Relation) Load(csvFile string, separator rune) {
 
 	defer file.Close()
 
-	for !file.EOFReached {
+	for lineNumber := 1; !file.EOFReached; lineNumber++ {
 		line, err := file.ReadLine()
 
 		if err != nil {
@@ -32,8 +33,26 @@ func (r Relation) Load(csvFile string, separator rune) {
 			panic(fmt.Sprintf("error during parsing: Found row with %d fields, relation contains %d fields instead (the file might be corrupted!)", len(fields), len(r.Columns)))
 		}
 
+		// rows are only inserted completely, so invalid rows (e.g. duplicate keys) are skipped
+		values := make([]interface{}, len(fields))
 		for index, fieldValue := range fields {
-			r.Columns[index].ImportRow(fieldValue)
+			values[index], err = parseValue(r.Columns[index].Signature.Type, fieldValue)
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = r.AddRow(values...)
+		}
+		if err != nil {
+			fmt.Printf("skipping line %d: %v\n", lineNumber, err)
+		}
+	}
+
+	// order preserving dictionaries allow evaluating range predicates and sorting on the codes
+	for _, col := range r.Columns {
+		if ds, isDict := col.Data.(*DictEncodedDataStore); isDict {
+			ds.SortDictionary()
 		}
 	}
 }
@@ -46,7 +65,7 @@ func (r Relation) Scan(colList []AttrInfo) Relationer {
 		included := false
 
 		for _, colHeader := range colList {
-			included = included || colHeader == col.Signature
+			included = included || col.Signature.matches(colHeader)
 		}
 
 		if included {
@@ -104,11 +123,19 @@ var compFuncs = map[DataTypes]map[Comparison]CompFunc{
 // comp defines the type of comparison.
 // compVal is the value used for the comparison.
 func (r Relation) Select(col AttrInfo, comp Comparison, compVal interface{}) Relationer {
+	return r.ParallelSelect(col, comp, compVal, NumWorkers)
+}
+
+// ParallelSelect implements Select using up to numWorkers goroutines (see scanRows). Predicates
+// supported by an index of the column are evaluated using the index instead. The output columns
+// reference the matching rows (in row order) instead of copying their values (see
+// PositionListDataStore).
+func (r Relation) ParallelSelect(col AttrInfo, comp Comparison, compVal interface{}, numWorkers int) Relationer {
 	result := Relation{Name: r.Name, Columns: []Column{}}
 
 	var filterColumn Column
 	for _, cols := range r.Columns {
-		if cols.Signature == col {
+		if cols.Signature.matches(col) {
 			filterColumn = cols
 		}
 
@@ -116,38 +143,180 @@ func (r Relation) Select(col AttrInfo, comp Comparison, compVal interface{}) Rel
 		result.Columns = append(result.Columns, newCol)
 	}
 
-	copyRow := func(rowIndex int) {
-		for colIndex, col := range r.Columns {
-			value, _ := col.GetRow(rowIndex)
-			result.Columns[colIndex].AddRow(col.Signature.Type, value)
-		}
-	}
-
-	var compFunc CompFunc
 	typeCompFuncs, found := compFuncs[filterColumn.Signature.Type]
 	if found {
-		compFunc, found = typeCompFuncs[comp]
+		_, found = typeCompFuncs[comp]
 	}
 
-	if !found {
+	if !found && comp != IN {
 		fmt.Print("comparison func not found")
 		return result
 	}
 
-	for rowIndex := 0; rowIndex < filterColumn.GetNumRows(); rowIndex++ {
-		value, err := filterColumn.GetRow(rowIndex)
+	rows, err := filterColumn.selectRows(comp, compVal, numWorkers)
+	if err != nil {
+		fmt.Printf("encountered unexpected error: %#v", err)
+		return nil
+	}
+
+	for colIndex := range r.Columns {
+		result.Columns[colIndex] = newColumnView(r.Columns[colIndex].Signature, &r.Columns[colIndex], rows)
+	}
+
+	return result
+}
+
+// SelectWhere returns the rows satisfying all (AND) or at least one (OR) of the predicates. The
+// matching rows of the predicates are combined as bitmaps, so predicates answered by bitmap
+// indexes (see BitmapIndex) don't need to be converted into row lists. Like Select, the output
+// columns reference the rows of r. Without predicates, r is returned unchanged.
+func (r Relation) SelectWhere(op LogicalOp, predicates []Predicate) Relationer {
+	if len(predicates) == 0 {
+		return r
+	}
+
+	var matches Bitmap
+	for predIndex, pred := range predicates {
+		var filterColumn *Column
+		for colIndex := range r.Columns {
+			if r.Columns[colIndex].Signature.matches(pred.Col) {
+				filterColumn = &r.Columns[colIndex]
+			}
+		}
+
+		if filterColumn == nil {
+			fmt.Printf("column %s not found", pred.Col.Name)
+			return nil
+		}
+
+		predMatches, err := filterColumn.selectBitmap(pred.Comp, pred.Value, NumWorkers)
 		if err != nil {
 			fmt.Printf("encountered unexpected error: %#v", err)
 			return nil
 		}
 
-		if compFunc(value, compVal) {
-			copyRow(rowIndex)
+		switch {
+		case predIndex == 0:
+			matches = predMatches
+		case op == AND:
+			matches = matches.And(predMatches)
+		default:
+			matches = matches.Or(predMatches)
 		}
 	}
+
+	rows := matches.Rows()
+	result := Relation{Name: r.Name, Columns: []Column{}}
+	for colIndex := range r.Columns {
+		result.Columns = append(result.Columns, newColumnView(r.Columns[colIndex].Signature, &r.Columns[colIndex], rows))
+	}
 	return result
 }
 
+// selectRows returns the indices of all rows of col satisfying the predicate (value comp compVal)
+// in ascending order. SORTED columns are searched using binary search and indexes supporting the
+// predicate are used instead of scanning the column. IN predicates without a supporting index are
+// evaluated in a single scan (see scanRows).
+func (col *Column) selectRows(comp Comparison, compVal interface{}, numWorkers int) ([]int, error) {
+	if rows, sorted, err := col.sortedRows(comp, compVal); sorted {
+		return rows, err
+	}
+	if rows, indexed := col.lookupIndex(comp, compVal); indexed {
+		return rows, nil
+	}
+
+	if _, found := compFuncs[col.Signature.Type][comp]; !found && comp != IN {
+		return nil, errors.New("comparison func not found")
+	}
+	return scanRows(col, comp, compVal, numWorkers)
+}
+
+// selectBitmap returns the bitmap of all rows of col satisfying the predicate
+// (value comp compVal), using a bitmap index if possible (see selectRows).
+func (col *Column) selectBitmap(comp Comparison, compVal interface{}, numWorkers int) (Bitmap, error) {
+	if matches, found := col.lookupBitmap(comp, compVal); found {
+		return matches, nil
+	}
+
+	rows, err := col.selectRows(comp, compVal, numWorkers)
+	if err != nil {
+		return Bitmap{}, err
+	}
+	return bitmapFromRows(rows), nil
+}
+
+// scanRows returns the indices of all rows of col satisfying the predicate (value comp compVal) in
+// ascending order. The row range gets split into one partition per worker and the predicate is
+// evaluated concurrently on every partition.
+func scanRows(col *Column, comp Comparison, compVal interface{}, numWorkers int) ([]int, error) {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	evaluator, onEncodedData := col.Data.(PredicateEvaluator)
+
+	// all values of an IN predicate are checked in a single scan using a set of the values, which
+	// is only supported on the decompressed vectors
+	if comp == IN {
+		set, err := newValueSet(col.Signature.Type, compVal)
+		if err != nil {
+			return nil, err
+		}
+		compVal, onEncodedData = set, false
+	}
+
+	partitions := splitRows(col.GetNumRows(), numWorkers)
+	positions := make([][]int, len(partitions))
+	errs := make([]error, len(partitions))
+
+	// blocks which can't match according to the zone map are skipped, the predicate is evaluated
+	// directly on the encoded data if the DataStore supports it, else batch by batch on the
+	// decompressed filter vectors
+	parallelFor(len(partitions), numWorkers, func(partIndex int) {
+		vec := Vector{}
+		batchPositions := make([]int, 0, BatchSize)
+
+		for _, candidates := range col.Zones.candidateRanges(partitions[partIndex].Start, partitions[partIndex].End, comp, compVal) {
+			if onEncodedData {
+				positions[partIndex], errs[partIndex] = evaluator.SelectRows(candidates.Start, candidates.End, comp, compVal, positions[partIndex])
+				if errs[partIndex] != nil {
+					return
+				}
+				continue
+			}
+
+			for start := candidates.Start; start < candidates.End; start += BatchSize {
+				end := start + BatchSize
+				if end > candidates.End {
+					end = candidates.End
+				}
+
+				if err := readVector(col, start, end, &vec); err != nil {
+					errs[partIndex] = err
+					return
+				}
+
+				batchPositions = selectVector(&vec, comp, compVal, nil, batchPositions[:0])
+				for _, position := range batchPositions {
+					positions[partIndex] = append(positions[partIndex], start+position)
+				}
+			}
+		}
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows := []int{}
+	for _, partPositions := range positions {
+		rows = append(rows, partPositions...)
+	}
+	return rows, nil
+}
+
 // Print should output the relation to the standard output in record
 // representation.
 func (r Relation) Print() {
@@ -507,236 +676,40 @@ func (r Relation) GetRawData() (cols []interface{}, sigs []AttrInfo) {
 	return cols, sigs
 }
 
+// Materialize returns a Relationer whose columns store their own values instead of referencing
+// the rows of other columns (see PositionListDataStore).
+func (r Relation) Materialize() Relationer {
+	output := Relation{Name: r.Name, Columns: []Column{}}
+
+	for _, col := range r.Columns {
+		output.Columns = append(output.Columns, col.Materialize())
+	}
+
+	return output
+}
+
 // HashJoin should implement the hash join operator between two relations.
 // rightRelation is the right relation for the hash join
 // joinType specifies the kind of hash join (inner, outer, semi ...)
 // compType specifies the comparison type for the join.
 // The join may be executed on one or more columns of each relation.
 func (r Relation) HashJoin(col1 []AttrInfo, rightRelation Relationer, col2 []AttrInfo, joinType JoinType, compType Comparison) Relationer {
-	if compType != EQ {
-		panic("HashJoin requires an equijoin predicate")
-	}
-
-	// get both relations as *Relation, left = the one with fewer entries
-	rightR, isRelation := (rightRelation).(Relation)
-
-	if !isRelation {
-		panic("unknown relation type")
-	}
-
-	right := &rightR
-	left := &r
-
-	output := Relation{Name: r.Name + " x " + right.Name, Columns: []Column{}}
-
-	// create hash table with left values
-	findCol := func(rel *Relation, colSig AttrInfo) *Column {
-		for colIndex, col := range rel.Columns {
-			if col.Signature == colSig {
-				return &rel.Columns[colIndex]
-			}
-		}
-
-		return nil
-	}
-
-	createHashTable := func(col *Column) interface{} {
-		switch col.Signature.Type {
-		case INT:
-			hash := map[int][]int{}
-
-			for i := 0; i < col.GetNumRows(); i++ {
-				value, _ := col.GetRow(i)
-				hash[value.(int)] = append(hash[value.(int)], i)
-			}
-
-			return hash
-
-		case FLOAT:
-			hash := map[float64][]int{}
-
-			for i := 0; i < col.GetNumRows(); i++ {
-				value, _ := col.GetRow(i)
-				hash[value.(float64)] = append(hash[value.(float64)], i)
-			}
-
-			return hash
-		case STRING:
-			hash := map[string][]int{}
-
-			for i := 0; i < col.GetNumRows(); i++ {
-				value, _ := col.GetRow(i)
-				hash[value.(string)] = append(hash[value.(string)], i)
-			}
-
-			return hash
-		}
-
-		panic("unknown failure (type unknown?)")
-	}
-
-	hashTables := []interface{}{}
-
-	createHashTables := func(rel *Relation, cols []AttrInfo) {
-		for i := 0; i < len(cols); i++ {
-			hashTables = append(hashTables, createHashTable(findCol(rel, cols[i])))
-		}
-	}
-
-	getMatch := func(hashTable interface{}, col *Column, row int) []int {
-		switch col.Signature.Type {
-		case INT:
-			hash := hashTable.(map[int][]int)
-			value, _ := col.GetRow(row)
-			return hash[value.(int)]
-		case FLOAT:
-			hash := hashTable.(map[float64][]int)
-			value, _ := col.GetRow(row)
-			return hash[value.(float64)]
-		case STRING:
-			hash := hashTable.(map[string][]int)
-			value, _ := col.GetRow(row)
-			return hash[value.(string)]
-		}
-		return []int{}
-	}
-
-	joinMatches := func(oldMatches, newMatches []int) []int {
-		output := []int{}
-
-		for i := 0; i < len(newMatches); i++ {
-			for j := 0; j < len(oldMatches); j++ {
-				if newMatches[i] == oldMatches[j] {
-					output = append(output, newMatches[i])
-					break
-				}
-			}
-		}
-
-		return output
-	}
-
-	checkRow := func(rel *Relation, cols []AttrInfo, rowIndex int) []int {
-		matches := getMatch(hashTables[0], findCol(rel, cols[0]), rowIndex)
-		for i := 1; i < len(cols) && len(matches) > 0; i++ {
-			matches = joinMatches(matches, getMatch(hashTables[i], findCol(rel, cols[i]), rowIndex))
-		}
-		return matches
-	}
-
-	maxLeftRows := left.Columns[0].GetNumRows()
-	maxRightRows := right.Columns[0].GetNumRows()
-	leftIndices := []int{}
-	rightIndices := []int{}
-
-	addOutputCols := func(base *Relation, tableName string, nullable bool) {
-		if nullable {
-			panic("NULL values not implemented")
-		}
-		for _, col := range base.Columns {
-			signature := AttrInfo{Name: tableName + "." + col.Signature.Name, Enc: col.Signature.Enc, Type: col.Signature.Type}
-			output.Columns = append(output.Columns, NewColumn(signature))
-		}
-	}
-
-	copyColumn := func(source *Column, dest *Column, indices []int) {
-		for _, row := range indices {
-			value, _ := source.GetRow(row)
-			dest.AddRow(source.Signature.Type, value)
-		}
-	}
-
-	copyLeftValues := func(indices []int) {
-		for colIndex := range left.Columns {
-			copyColumn(&left.Columns[colIndex], &output.Columns[colIndex], indices)
-		}
-	}
-
-	copyRightValues := func(indices []int) {
-		numLeftCols := len(left.Columns)
-		for colIndex := range right.Columns {
-			copyColumn(&right.Columns[colIndex], &output.Columns[numLeftCols+colIndex], indices)
-		}
-	}
-
-	innerJoin := func() {
-		if maxLeftRows < maxRightRows {
-			createHashTables(left, col1)
-
-			for i := 0; i < maxRightRows; i++ {
-				matches := checkRow(right, col2, i)
-
-				if len(matches) > 0 {
-					for j := 0; j < len(matches); j++ {
-						leftIndices = append(leftIndices, matches[j])
-						rightIndices = append(rightIndices, i)
-					}
-				}
-			}
-		} else {
-			createHashTables(right, col2)
-
-			for i := 0; i < maxLeftRows; i++ {
-				matches := checkRow(left, col1, i)
-
-				if len(matches) > 0 {
-					for j := 0; j < len(matches); j++ {
-						leftIndices = append(leftIndices, i)
-						rightIndices = append(rightIndices, matches[j])
-					}
-				}
-			}
-		}
-	}
-
-	semiJoin := func() {
-		createHashTables(right, col2)
-
-		for i := 0; i < maxLeftRows; i++ {
-			matches := checkRow(left, col1, i)
-
-			if len(matches) > 0 {
-				leftIndices = append(leftIndices, i)
-			}
-		}
-	}
-
-	switch joinType {
-	case INNER:
-		addOutputCols(left, left.Name, false)
-		addOutputCols(right, right.Name, false)
-		innerJoin()
-		copyLeftValues(leftIndices)
-		copyRightValues(rightIndices)
-	case SEMI:
-		output.Name = left.Name + " (x " + right.Name + ")"
-		addOutputCols(left, left.Name, false)
-		semiJoin()
-		copyLeftValues(leftIndices)
-	case RIGHTOUTER:
-		panic("NULL values not implemented")
-	case LEFTOUTER:
-		panic("NULL values not implemented")
-	default:
-		panic("unknown join type")
-	}
-
-	return output
+	return r.ParallelHashJoin(col1, rightRelation, col2, joinType, compType, NumWorkers)
 }
 
 // Limit returns a Relationer with a maximum of rowCount rows, starting from startRowIndex
 func (r Relation) Limit(startRowIndex, rowCount int) Relationer {
 	output := Relation{Name: r.Name, Columns: []Column{}}
 
-	for _, col := range r.Columns {
-		newCol := NewColumn(col.Signature)
-
-		for i := 0; i < rowCount && startRowIndex+i < col.GetNumRows(); i++ {
-			value, _ := col.GetRow(i)
-			newCol.AddRow(col.Signature.Type, value)
+	rows := []int{}
+	if len(r.Columns) > 0 {
+		for i := 0; i < rowCount && startRowIndex+i < r.Columns[0].GetNumRows(); i++ {
+			rows = append(rows, startRowIndex+i)
 		}
+	}
 
-		output.Columns = append(output.Columns, newCol)
+	for colIndex, col := range r.Columns {
+		output.Columns = append(output.Columns, newColumnView(col.Signature, &r.Columns[colIndex], rows))
 	}
 
 	return output
@@ -744,6 +717,14 @@ func (r Relation) Limit(startRowIndex, rowCount int) Relationer {
 
 // GroupBy returns a Relationer grouped by the given column
 func (r Relation) GroupBy(groupColumn AttrInfo) Relationer {
+	return r.ParallelGroupBy(groupColumn, NumWorkers)
+}
+
+// ParallelGroupBy implements GroupBy using up to numWorkers goroutines.
+// Every worker groups a contiguous partition of the rows into its own (thread-local) hash table.
+// Afterwards, the partial groups are merged in partition order, so the groups are ordered by their
+// first occurrence and the rows of every group stay in ascending order.
+func (r Relation) ParallelGroupBy(groupColumn AttrInfo, numWorkers int) Relationer {
 	output := Relation{Name: r.Name, Columns: []Column{}}
 
 	var sourceCol *Column
@@ -756,74 +737,86 @@ func (r Relation) GroupBy(groupColumn AttrInfo) Relationer {
 			panic("cannot group an already grouped relation")
 		}
 
-		if modSig == groupColumn {
+		if modSig.matches(groupColumn) {
 			sourceCol = &r.Columns[colIndex]
 			output.Columns = append(output.Columns, NewColumn(modSig))
 			destCol = &output.Columns[colIndex]
 		} else {
-			modSig.Flags = modSig.Flags | GROUPED
+			// the values of the groups aren't sorted anymore
+			modSig.Flags = modSig.Flags&^SORTED | GROUPED
 			output.Columns = append(output.Columns, NewColumn(modSig))
 		}
 	}
 
-	var groupedIndices [][]int
-	maxRows := sourceCol.GetNumRows()
-
 	switch sourceCol.Signature.Type {
-	case INT:
-		hash := map[int][]int{}
+	case INT, FLOAT, STRING:
+	default:
+		panic("unknown type")
+	}
 
-		for index := 0; index < maxRows; index++ {
-			value, err := sourceCol.GetRow(index)
-			if err != nil {
-				panic(err)
-			}
-			hash[value.(int)] = append(hash[value.(int)], index)
-		}
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
 
-		for value, indices := range hash {
-			groupedIndices = append(groupedIndices, indices)
-			destCol.AddRow(sourceCol.Signature.Type, value)
-		}
-	case STRING:
-		hash := map[string][]int{}
+	type partialGroups struct {
+		keys    []interface{}
+		indices [][]int
+		err     error
+	}
 
-		for index := 0; index < maxRows; index++ {
+	partitions := splitRows(sourceCol.GetNumRows(), numWorkers)
+	partials := make([]partialGroups, len(partitions))
+
+	// phase 1: thread-local grouping
+	parallelFor(len(partitions), numWorkers, func(partIndex int) {
+		partial := &partials[partIndex]
+		hash := map[interface{}]int{}
+
+		for index := partitions[partIndex].Start; index < partitions[partIndex].End; index++ {
 			value, err := sourceCol.GetRow(index)
 			if err != nil {
-				panic(err)
+				partial.err = err
+				return
 			}
-			hash[value.(string)] = append(hash[value.(string)], index)
-		}
 
-		for value, indices := range hash {
-			groupedIndices = append(groupedIndices, indices)
-			destCol.AddRow(sourceCol.Signature.Type, value)
+			groupIndex, found := hash[value]
+			if !found {
+				groupIndex = len(partial.keys)
+				hash[value] = groupIndex
+				partial.keys = append(partial.keys, value)
+				partial.indices = append(partial.indices, []int{})
+			}
+			partial.indices[groupIndex] = append(partial.indices[groupIndex], index)
 		}
-	case FLOAT:
-		hash := map[float64][]int{}
+	})
 
-		for index := 0; index < maxRows; index++ {
-			value, err := sourceCol.GetRow(index)
-			if err != nil {
-				panic(err)
-			}
-			hash[value.(float64)] = append(hash[value.(float64)], index)
+	// phase 2: merge the partial groups
+	var groupedIndices [][]int
+	hash := map[interface{}]int{}
+
+	for _, partial := range partials {
+		if partial.err != nil {
+			panic(partial.err)
 		}
 
-		for value, indices := range hash {
-			groupedIndices = append(groupedIndices, indices)
-			destCol.AddRow(sourceCol.Signature.Type, value)
+		for localIndex, value := range partial.keys {
+			groupIndex, found := hash[value]
+			if !found {
+				groupIndex = len(groupedIndices)
+				hash[value] = groupIndex
+				groupedIndices = append(groupedIndices, []int{})
+				destCol.AddRow(sourceCol.Signature.Type, value)
+			}
+			groupedIndices[groupIndex] = append(groupedIndices[groupIndex], partial.indices[localIndex]...)
 		}
-	default:
-		panic("unknown type")
 	}
 
-	for colIndex := range output.Columns {
+	// phase 3: fill the grouped columns, one goroutine per column
+	parallelFor(len(output.Columns), numWorkers, func(colIndex int) {
 		dest := &output.Columns[colIndex]
 		source := &r.Columns[colIndex]
-		if dest.GetNumRows() > 0 {
-			continue
+		if source == sourceCol {
+			return
 		}
 
 		switch source.Signature.Type {
@@ -861,7 +854,7 @@ func (r Relation) GroupBy(groupColumn AttrInfo) Relationer {
 				dest.AddRow(FLOAT, groupValues)
 			}
 		}
-	}
+	})
 
 	return output
 }
@@ -869,25 +862,33 @@ func (r Relation) GroupBy(groupColumn AttrInfo) Relationer {
 // Aggregate should implement the grouping and aggregation of columns.
 // groupBy specifies on which columns it should be grouped.
 // aggregate defines the column on which the aggrFunc should be applied.
-// currently not implemented
 func (r Relation) Aggregate(aggregate AttrInfo, aggrFunc AggrFunc) Relationer {
+	return r.ParallelAggregate(aggregate, aggrFunc, NumWorkers)
+}
+
+// ParallelAggregate implements Aggregate using up to numWorkers goroutines.
+// The groups are read batch by batch via GetRange and aggregated by the typed kernels (see
+// aggregateGroups), every worker handles one batch at a time. The results are merged in group order.
+func (r Relation) ParallelAggregate(aggregate AttrInfo, aggrFunc AggrFunc, numWorkers int) Relationer {
 	output := Relation{Name: r.Name, Columns: []Column{}}
 
 	var aggrSourceCol *Column
 	var aggrDestCol *Column
 
 	copyColumn := func(source *Column, dest *Column) {
-		for i := 0; i < source.GetNumRows(); i++ {
-			value, _ := source.GetRow(i)
-			dest.AddRow(source.Signature.Type, value)
+		values, err := source.GetRange(0, source.GetNumRows())
+		if err != nil {
+			panic(err)
+		}
+		if _, err := dest.AddRows(source.Signature.Type, values); err != nil {
+			panic(err)
 		}
 	}
 
 	for colIndex, col := range r.Columns {
-
-		if col.Signature == aggregate {
+		if col.Signature.matches(aggregate) {
 			sig := col.Signature
-			sig.Flags &^= GROUPED
+			sig.Flags &^= GROUPED | SORTED
 			if aggrFunc == COUNT {
 				sig.Type = INT
 			}
@@ -898,7 +899,6 @@ func (r Relation) Aggregate(aggregate AttrInfo, aggrFunc AggrFunc) Relationer {
 			aggrDestCol = &output.Columns[colIndex]
 		} else {
 			output.Columns = append(output.Columns, NewColumn(col.Signature))
-			copyColumn(&r.Columns[colIndex], &output.Columns[colIndex])
 		}
 	}
 
@@ -906,98 +906,45 @@ func (r Relation) Aggregate(aggregate AttrInfo, aggrFunc AggrFunc) Relationer {
 		panic("invalid column specified")
 	}
 
-	for i := 0; i < aggrSourceCol.GetNumRows(); i++ {
-		sourceValue, _ := aggrSourceCol.GetRow(i)
-
-		switch aggrSourceCol.Signature.Type {
-		case INT:
-			groupValue, _ := sourceValue.([]int)
-			aggrValue := groupValue[0]
-
-			switch aggrFunc {
-			case SUM:
-				for j := 1; i < len(groupValue); j++ {
-					aggrValue += groupValue[j]
-				}
-
-			case COUNT:
-				aggrValue = len(groupValue)
-
-			case MIN:
-				for j := 1; j < len(groupValue); j++ {
-					if groupValue[j] < aggrValue {
-						aggrValue = groupValue[j]
-					}
-				}
-
-			case MAX:
-				for j := 1; j < len(groupValue); j++ {
-					if groupValue[j] > aggrValue {
-						aggrValue = groupValue[j]
-					}
-				}
-			}
-			aggrDestCol.AddRow(INT, aggrValue)
-
-		case FLOAT:
-			groupValue, _ := sourceValue.([]float64)
-			aggrValue := groupValue[0]
-
-			switch aggrFunc {
-			case SUM:
-				for j := 1; j < len(groupValue); j++ {
-					aggrValue += groupValue[j]
-				}
+	if aggrSourceCol.Signature.Type == STRING && aggrFunc == SUM {
+		panic("sum is not supported for strings")
+	}
 
-			case COUNT:
-				count := len(groupValue)
-				aggrDestCol.AddRow(INT, count)
-				continue
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
 
-			case MIN:
-				for j := 1; j < len(groupValue); j++ {
-					if groupValue[j] < aggrValue {
-						aggrValue = groupValue[j]
-					}
-				}
+	numGroups := aggrSourceCol.GetNumRows()
+	numBatches := (numGroups + BatchSize - 1) / BatchSize
+	aggrValues := make([]interface{}, numBatches)
 
-			case MAX:
-				for j := 1; j < len(groupValue); j++ {
-					if groupValue[j] > aggrValue {
-						aggrValue = groupValue[j]
-					}
+	// the first task copies the remaining columns, all other tasks aggregate one batch each
+	parallelFor(numBatches+1, numWorkers, func(task int) {
+		if task == 0 {
+			for colIndex := range r.Columns {
+				if &r.Columns[colIndex] != aggrSourceCol {
+					copyColumn(&r.Columns[colIndex], &output.Columns[colIndex])
 				}
 			}
-			aggrDestCol.AddRow(FLOAT, aggrValue)
-
-		case STRING:
-			groupValue := sourceValue.([]string)
-			aggrValue := groupValue[0]
-
-			switch aggrFunc {
-			case SUM:
-				panic("sum is not supported for strings")
+			return
+		}
 
-			case COUNT:
-				count := len(groupValue)
-				aggrDestCol.AddRow(INT, count)
-				continue
+		start := (task - 1) * BatchSize
+		end := start + BatchSize
+		if end > numGroups {
+			end = numGroups
+		}
 
-			case MIN:
-				for j := 1; j < len(groupValue); j++ {
-					if strings.Compare(groupValue[j], aggrValue) < 0 {
-						aggrValue = groupValue[j]
-					}
-				}
+		groups, err := aggrSourceCol.GetRange(start, end)
+		if err != nil {
+			panic(err)
+		}
+		aggrValues[task-1] = aggregateGroups(groups, aggrFunc)
+	})
 
-			case MAX:
-				for j := 1; j < len(groupValue); j++ {
-					if strings.Compare(groupValue[j], aggrValue) > 0 {
-						aggrValue = groupValue[j]
-					}
-				}
-			}
-			aggrDestCol.AddRow(STRING, aggrValue)
+	for _, batchValues := range aggrValues {
+		if _, err := aggrDestCol.AddRows(aggrDestCol.Signature.Type, batchValues); err != nil {
+			panic(err)
 		}
 	}
 
@@ -1022,6 +969,8 @@ func (r Relation) MergeSort(columns []AttrInfo, sortOrder SortOrder) Relationer
 		Column  *Column
 		Compare CompFunc
 		Equals  CompFunc
+		// Codes contains the codes of all rows if Column uses an order preserving dictionary
+		Codes []int
 	}
 
 	sortData := make([]SortData, len(columns))
@@ -1029,6 +978,14 @@ func (r Relation) MergeSort(columns []AttrInfo, sortOrder SortOrder) Relationer
 
 	compare := func(aIndex int, bIndex int) bool {
 		for _, curStep := range sortData {
+			if curStep.Codes != nil {
+				aCode, bCode := curStep.Codes[aIndex], curStep.Codes[bIndex]
+				if aCode != bCode {
+					return (aCode < bCode) == (sortOrder == ASC)
+				}
+				continue
+			}
+
 			aValue, _ := curStep.Column.GetRow(aIndex)
 			bValue, _ := curStep.Column.GetRow(bIndex)
 
@@ -1087,12 +1044,13 @@ func (r Relation) MergeSort(columns []AttrInfo, sortOrder SortOrder) Relationer
 
 		for index, signature := range columns {
 			for colIndex, col := range r.Columns {
-				if col.Signature == signature {
+				if col.Signature.matches(signature) {
 					sortData[index] = SortData{
 						Column:  &r.Columns[colIndex],
 						Equals:  compFuncs[signature.Type][EQ],
 						Compare: compFuncs[signature.Type][compType],
 					}
+					_, sortData[index].Codes = orderedCodes(&r.Columns[colIndex])
 				}
 			}
 		}
@@ -1100,10 +1058,6 @@ func (r Relation) MergeSort(columns []AttrInfo, sortOrder SortOrder) Relationer
 		// init output Relation
 		output.Name = r.Name
 		output.Columns = []Column{}
-
-		for _, col := range r.Columns {
-			output.Columns = append(output.Columns, NewColumn(col.Signature))
-		}
 	}
 
 	createIota := func(length int) []int {
@@ -1116,16 +1070,17 @@ func (r Relation) MergeSort(columns []AttrInfo, sortOrder SortOrder) Relationer
 		return output
 	}
 
-	copyColValues := func(source *Column, dest *Column, order []int) {
-		for _, index := range order {
-			value, _ := source.GetRow(index)
-			dest.AddRow(source.Signature.Type, value)
-		}
-	}
-
+	// the output columns reference the rows in sorted order, only the first key column is sorted
+	// completely (the others only within the groups of equal preceding keys). NaN values aren't
+	// ordered, so a key column containing them isn't flagged as SORTED.
 	copyValues := func(indices []int) {
-		for colIndex := range r.Columns {
-			copyColValues(&r.Columns[colIndex], &output.Columns[colIndex], indices)
+		for colIndex, col := range r.Columns {
+			signature := col.Signature
+			signature.Flags &^= SORTED
+			if sortOrder == ASC && len(columns) > 0 && signature.Flags&GROUPED == 0 && signature.matches(columns[0]) && !col.containsNaN() {
+				signature.Flags |= SORTED
+			}
+			output.Columns = append(output.Columns, newColumnView(signature, &r.Columns[colIndex], indices))
 		}
 	}
 
@@ -1150,10 +1105,20 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 		Compare CompFunc
 		Lesser  CompFunc
 		Equals  CompFunc
+		// LeftKeys and RightKeys contain comparable keys for all rows if both columns use order
+		// preserving dictionaries (see joinKeys)
+		LeftKeys  []int
+		RightKeys []int
 	}
 
-	right = right.MergeSort(rightCols, ASC).(Relation)
-	left := r.MergeSort(leftCols, ASC).(Relation)
+	// inputs already ordered by their join keys (e.g. SORTED columns) don't need to be sorted again
+	if !isSortedBy(findJoinColumns(&right, rightCols)) {
+		right = right.MergeSort(rightCols, ASC).(Relation)
+	}
+	left := r
+	if !isSortedBy(findJoinColumns(&left, leftCols)) {
+		left = r.MergeSort(leftCols, ASC).(Relation)
+	}
 	output := Relation{Columns: []Column{}}
 
 	leftIndices := []int{}
@@ -1164,16 +1129,6 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 	maxRightRows := right.Columns[0].GetNumRows()
 	var mergeData []MergeData
 
-	addOutputCols := func(base *Relation, tableName string, nullable bool) {
-		if nullable {
-			panic("NULL values not implemented")
-		}
-		for _, col := range base.Columns {
-			signature := AttrInfo{Name: tableName + "." + col.Signature.Name, Enc: col.Signature.Enc, Type: col.Signature.Type}
-			output.Columns = append(output.Columns, NewColumn(signature))
-		}
-	}
-
 	getMergeData := func() []MergeData {
 		output := []MergeData{}
 
@@ -1181,14 +1136,14 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 			entry := MergeData{}
 
 			for colIndex, col := range left.Columns {
-				if col.Signature == signature {
+				if col.Signature.matches(signature) {
 					entry.Left = &left.Columns[colIndex]
 					break
 				}
 			}
 
 			for colIndex, col := range right.Columns {
-				if col.Signature == rightCols[sigIndex] {
+				if col.Signature.matches(rightCols[sigIndex]) {
 					entry.Right = &right.Columns[colIndex]
 					break
 				}
@@ -1201,6 +1156,12 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 			entry.Equals = compFuncs[signature.Type][EQ]
 			entry.Lesser = compFuncs[signature.Type][LT]
 
+			leftDict, leftCodes := orderedCodes(entry.Left)
+			rightDict, rightCodes := orderedCodes(entry.Right)
+			if leftDict != nil && rightDict != nil && leftDict.DataType == rightDict.DataType {
+				entry.LeftKeys, entry.RightKeys = joinKeys(leftDict, leftCodes, rightDict, rightCodes)
+			}
+
 			output = append(output, entry)
 		}
 
@@ -1209,6 +1170,13 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 
 	isEqual := func(leftIndex int, rightIndex int) bool {
 		for _, entry := range mergeData {
+			if entry.LeftKeys != nil {
+				if entry.LeftKeys[leftIndex] != entry.RightKeys[rightIndex] {
+					return false
+				}
+				continue
+			}
+
 			leftValue, _ := entry.Left.GetRow(leftIndex)
 			rightValue, _ := entry.Right.GetRow(rightIndex)
 			if !entry.Equals(leftValue, rightValue) {
@@ -1220,6 +1188,13 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 
 	isLesser := func(leftIndex, rightIndex int) bool {
 		for _, entry := range mergeData {
+			if entry.LeftKeys != nil {
+				if entry.LeftKeys[leftIndex] != entry.RightKeys[rightIndex] {
+					return entry.LeftKeys[leftIndex] < entry.RightKeys[rightIndex]
+				}
+				continue
+			}
+
 			leftValue, _ := entry.Left.GetRow(leftIndex)
 			rightValue, _ := entry.Right.GetRow(rightIndex)
 
@@ -1350,40 +1325,17 @@ func (r Relation) MergeJoin(leftCols []AttrInfo, rightRelation Relationer, right
 		}
 	}
 
-	copyColumn := func(source *Column, dest *Column, indices []int) {
-		for _, row := range indices {
-			value, _ := source.GetRow(row)
-			dest.AddRow(source.Signature.Type, value)
-		}
-	}
-
-	copyLeftValues := func(indices []int) {
-		for colIndex := range left.Columns {
-			copyColumn(&left.Columns[colIndex], &output.Columns[colIndex], indices)
-		}
-	}
-
-	copyRightValues := func(indices []int) {
-		numLeftCols := len(left.Columns)
-		for colIndex := range right.Columns {
-			copyColumn(&right.Columns[colIndex], &output.Columns[numLeftCols+colIndex], indices)
-		}
-	}
-
 	switch joinType {
 	case INNER:
 		output.Name = r.Name + " x " + rightRelation.(Relation).Name
-		addOutputCols(&left, r.Name, false)
-		addOutputCols(&right, rightRelation.(Relation).Name, false)
 		innerJoin()
-		copyLeftValues(leftIndices)
-		copyRightValues(rightIndices)
+		output.Columns = append(output.Columns, joinOutputColumns(&left, r.Name, leftIndices)...)
+		output.Columns = append(output.Columns, joinOutputColumns(&right, rightRelation.(Relation).Name, rightIndices)...)
 		break
 	case SEMI:
 		output.Name = r.Name + " (x " + rightRelation.(Relation).Name + ")"
-		addOutputCols(&left, r.Name, false)
 		semiJoin()
-		copyLeftValues(leftIndices)
+		output.Columns = append(output.Columns, joinOutputColumns(&left, r.Name, leftIndices)...)
 		break
 	case LEFTOUTER:
 		// handle null values on left
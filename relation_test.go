@@ -2,6 +2,7 @@ package csgo
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"reflect"
 	"testing"
@@ -182,6 +183,154 @@ func TestRelationSelect(t *testing.T) {
 	}
 }
 
+func TestRelationParallelSelect(t *testing.T) {
+	ints := []int{}
+	floats := []float64{}
+	strs := []string{}
+	for i := 0; i < 10000; i++ {
+		ints = append(ints, (i*37)%101)
+		floats = append(floats, float64(i%17)/4)
+		strs = append(strs, fmt.Sprintf("val%d", i%5))
+	}
+
+	r := Relation{Name: "testRel", Columns: []Column{
+		NewColumnWithData(AttrInfo{Name: "testCol1", Type: INT, Enc: NOCOMP}, ints),
+		NewColumnWithData(AttrInfo{Name: "testCol2", Type: STRING, Enc: NOCOMP}, strs),
+		NewColumnWithData(AttrInfo{Name: "testCol3", Type: FLOAT, Enc: NOCOMP}, floats),
+	}}
+
+	cases := []struct {
+		col   AttrInfo
+		comp  Comparison
+		value interface{}
+	}{
+		{col: AttrInfo{Name: "testCol1", Type: INT, Enc: NOCOMP}, comp: LT, value: int(42)},
+		{col: AttrInfo{Name: "testCol2", Type: STRING, Enc: NOCOMP}, comp: EQ, value: "val3"},
+		{col: AttrInfo{Name: "testCol3", Type: FLOAT, Enc: NOCOMP}, comp: GEQ, value: float64(2.5)},
+	}
+
+	for testcaseID, testcase := range cases {
+		expected, _ := r.ParallelSelect(testcase.col, testcase.comp, testcase.value, 1).GetRawData()
+
+		for _, numWorkers := range []int{2, 3, 16} {
+			resultData, _ := r.ParallelSelect(testcase.col, testcase.comp, testcase.value, numWorkers).GetRawData()
+
+			if !reflect.DeepEqual(expected, resultData) {
+				t.Errorf("testcase %d: result with %d workers does not match the single threaded result", testcaseID, numWorkers)
+			}
+		}
+	}
+}
+
+func TestRelationSelectIn(t *testing.T) {
+	values := make([]int, 3*ZoneBlockSize)
+	for i := range values {
+		values[i] = (i / 10) % 200
+	}
+
+	cases := []struct {
+		compVal  interface{}
+		expected func(value int) bool
+	}{
+		{compVal: []int{7, 3, 7, 150}, expected: func(value int) bool { return value == 3 || value == 7 || value == 150 }},
+		{compVal: []int{-1, 500}, expected: func(value int) bool { return false }},
+		{compVal: []int{}, expected: func(value int) bool { return false }},
+	}
+
+	for _, enc := range []Compression{NOCOMP, RLE, DICT, FOR} {
+		r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(AttrInfo{"col", INT, enc, 0}, values)}}
+
+		for testCaseID, testCase := range cases {
+			expected := []int{}
+			for _, value := range values {
+				if testCase.expected(value) {
+					expected = append(expected, value)
+				}
+			}
+
+			output, _ := r.Select(AttrInfo{"col", INT, enc, 0}, IN, testCase.compVal).GetRawData()
+			if !reflect.DeepEqual(output, []interface{}{expected}) {
+				t.Errorf("%v: test case %d does not match", enc, testCaseID)
+			}
+		}
+
+		if _, err := r.Columns[0].selectRows(IN, []string{"a"}, 1); err == nil {
+			t.Errorf("%v: IN with mismatching type succeeded", enc)
+		}
+	}
+}
+
+func TestRelationSelectWhere(t *testing.T) {
+	countries := []string{}
+	payments := []string{}
+	prices := []int{}
+	for i := 0; i < 5000; i++ {
+		countries = append(countries, []string{"Argentina", "Brazil", "Chile", "Denmark"}[(i*7)%4])
+		payments = append(payments, []string{"Visa", "Amex", "Mastercard"}[i%3])
+		prices = append(prices, (i*37)%1201)
+	}
+
+	createRelation := func(flags ColumnFlags) Relation {
+		return Relation{Name: "sales", Columns: []Column{
+			NewColumnWithData(AttrInfo{"Country", STRING, NOCOMP, flags}, countries),
+			NewColumnWithData(AttrInfo{"Payment", STRING, DICT, flags}, payments),
+			NewColumnWithData(AttrInfo{"Price", INT, NOCOMP, 0}, prices),
+		}}
+	}
+
+	cases := []struct {
+		op         LogicalOp
+		predicates []Predicate
+		matches    func(row int) bool
+	}{
+		{op: AND, predicates: []Predicate{{AttrInfo{"Country", STRING, NOCOMP, 0}, EQ, "Chile"}, {AttrInfo{"Payment", STRING, DICT, 0}, NEQ, "Visa"}},
+			matches: func(row int) bool { return countries[row] == "Chile" && payments[row] != "Visa" }},
+		{op: OR, predicates: []Predicate{{AttrInfo{"Country", STRING, NOCOMP, 0}, IN, []string{"Brazil", "Peru", "Denmark"}}, {AttrInfo{"Price", INT, NOCOMP, 0}, LT, 100}},
+			matches: func(row int) bool {
+				return countries[row] == "Brazil" || countries[row] == "Denmark" || prices[row] < 100
+			}},
+		{op: AND, predicates: []Predicate{{AttrInfo{"Payment", STRING, DICT, 0}, IN, []string{"Amex"}}, {AttrInfo{"Price", INT, NOCOMP, 0}, GEQ, 600}, {AttrInfo{"Country", STRING, NOCOMP, 0}, NEQ, "Argentina"}},
+			matches: func(row int) bool {
+				return payments[row] == "Amex" && prices[row] >= 600 && countries[row] != "Argentina"
+			}},
+	}
+
+	for _, flags := range []ColumnFlags{0, BITMAPINDEX} {
+		r := createRelation(flags)
+
+		for testCaseID, testCase := range cases {
+			expected := []interface{}{[]string{}, []string{}, []int{}}
+			for row := range countries {
+				if testCase.matches(row) {
+					expected[0] = append(expected[0].([]string), countries[row])
+					expected[1] = append(expected[1].([]string), payments[row])
+					expected[2] = append(expected[2].([]int), prices[row])
+				}
+			}
+
+			predicates := []Predicate{}
+			for _, pred := range testCase.predicates {
+				if pred.Col.Type == STRING {
+					pred.Col.Flags = flags
+				}
+				predicates = append(predicates, pred)
+			}
+
+			output, _ := r.SelectWhere(testCase.op, predicates).GetRawData()
+			if !reflect.DeepEqual(output, expected) {
+				t.Errorf("test case %d with flags %d: output does not match", testCaseID, flags)
+			}
+
+			// single predicates match Select
+			output, _ = r.SelectWhere(AND, predicates[:1]).GetRawData()
+			selected, _ := r.Select(predicates[0].Col, predicates[0].Comp, predicates[0].Value).GetRawData()
+			if !reflect.DeepEqual(output, selected) {
+				t.Errorf("test case %d with flags %d: selection does not match", testCaseID, flags)
+			}
+		}
+	}
+}
+
 func TestRelationGetRawData(t *testing.T) {
 	cases := []struct {
 		rel  Relation
@@ -239,6 +388,28 @@ func TestRelationGetRawData(t *testing.T) {
 	}
 }
 
+func TestRelationLimit(t *testing.T) {
+	input := Relation{Name: "rel", Columns: []Column{NewColumnWithData(AttrInfo{"col", INT, NOCOMP, 0}, []int{1, 2, 3, 4, 5})}}
+
+	cases := []struct {
+		start    int
+		count    int
+		expected []int
+	}{
+		{start: 0, count: 2, expected: []int{1, 2}},
+		{start: 2, count: 2, expected: []int{3, 4}},
+		{start: 3, count: 10, expected: []int{4, 5}},
+		{start: 7, count: 1, expected: []int{}},
+	}
+
+	for testCaseID, testCase := range cases {
+		data, _ := input.Limit(testCase.start, testCase.count).GetRawData()
+		if !reflect.DeepEqual(data, []interface{}{testCase.expected}) {
+			t.Errorf("test case %d: expected %v, got %v", testCaseID, testCase.expected, data)
+		}
+	}
+}
+
 func TestRelationHashJoin(t *testing.T) {
 	cases := []struct {
 		left      Relation
@@ -365,7 +536,7 @@ func TestRelationHashJoin(t *testing.T) {
 	}
 
 	for testCaseID, testCase := range cases {
-		output := testCase.left.HashJoin(testCase.leftCols, testCase.right, testCase.rightCols, testCase.joinType, testCase.compType)
+		output := testCase.left.HashJoin(testCase.leftCols, testCase.right, testCase.rightCols, testCase.joinType, testCase.compType).(Relation).Materialize()
 
 		if !reflect.DeepEqual(output, testCase.output) {
 			t.Fail()
@@ -524,6 +695,49 @@ func TestRelationAggregate(t *testing.T) {
 	}
 }
 
+func TestRelationParallelGroupByAggregate(t *testing.T) {
+	keys := []int{}
+	values := []int{}
+	expectedSums := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		key := (i * 31) % 1999
+		keys = append(keys, key)
+		values = append(values, i)
+		expectedSums[key] += i
+	}
+
+	input := Relation{Name: "testInput", Columns: []Column{
+		NewColumnWithData(AttrInfo{"KEY", INT, NOCOMP, 0}, keys),
+		NewColumnWithData(AttrInfo{"VALUE", INT, NOCOMP, 0}, values),
+	}}
+
+	expected := input.ParallelGroupBy(AttrInfo{"KEY", INT, NOCOMP, 0}, 1).(Relation)
+	expectedAggr := expected.ParallelAggregate(AttrInfo{"VALUE", INT, NOCOMP, GROUPED}, SUM, 1).(Relation)
+
+	for _, numWorkers := range []int{2, 5, 16} {
+		output := input.ParallelGroupBy(AttrInfo{"KEY", INT, NOCOMP, 0}, numWorkers).(Relation)
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("grouping with %d workers does not match the single threaded result", numWorkers)
+		}
+
+		aggr := output.ParallelAggregate(AttrInfo{"VALUE", INT, NOCOMP, GROUPED}, SUM, numWorkers).(Relation)
+		if !reflect.DeepEqual(aggr, expectedAggr) {
+			t.Errorf("aggregation with %d workers does not match the single threaded result", numWorkers)
+		}
+	}
+
+	aggrData, _ := expectedAggr.GetRawData()
+	groupKeys, sums := aggrData[0].([]int), aggrData[1].([]int)
+	if len(groupKeys) != len(expectedSums) {
+		t.Errorf("expected %d groups, got %d", len(expectedSums), len(groupKeys))
+	}
+	for groupIndex, key := range groupKeys {
+		if sums[groupIndex] != expectedSums[key] {
+			t.Errorf("group %d: expected sum %d, got %d", key, expectedSums[key], sums[groupIndex])
+		}
+	}
+}
+
 func TestRelationMergeSort(t *testing.T) {
 	cases := []struct {
 		input     Relation
@@ -536,7 +750,7 @@ func TestRelationMergeSort(t *testing.T) {
 				NewColumnWithData(AttrInfo{"intCol1", INT, NOCOMP, 0}, []int{1, 3, 2, 4, 6, 5}),
 			}},
 			output: Relation{Name: "testInput", Columns: []Column{
-				NewColumnWithData(AttrInfo{"intCol1", INT, NOCOMP, 0}, []int{1, 2, 3, 4, 5, 6}),
+				NewColumnWithData(AttrInfo{"intCol1", INT, NOCOMP, SORTED}, []int{1, 2, 3, 4, 5, 6}),
 			}},
 			sortOrder: ASC,
 			cols:      []AttrInfo{{"intCol1", INT, NOCOMP, 0}},
@@ -554,7 +768,7 @@ func TestRelationMergeSort(t *testing.T) {
 	}
 
 	for testCaseID, testCase := range cases {
-		output := testCase.input.MergeSort(testCase.cols, testCase.sortOrder).(Relation)
+		output := testCase.input.MergeSort(testCase.cols, testCase.sortOrder).(Relation).Materialize()
 
 		if !reflect.DeepEqual(output, testCase.output) {
 			t.Fail()
@@ -563,6 +777,110 @@ func TestRelationMergeSort(t *testing.T) {
 	}
 }
 
+func TestRelationSortedColumn(t *testing.T) {
+	keys := []int{}
+	names := []string{}
+	for i := 0; i < 500; i++ {
+		keys = append(keys, (i*37)%101)
+		names = append(names, fmt.Sprintf("name%d", i%7))
+	}
+
+	keySig := AttrInfo{"key", INT, DELTA, 0}
+	nameSig := AttrInfo{"name", STRING, NOCOMP, 0}
+	r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(nameSig, names), NewColumnWithData(keySig, keys)}}
+
+	sorted := r.MergeSort([]AttrInfo{keySig, nameSig}, ASC).(Relation)
+	if sorted.Columns[1].Signature.Flags != SORTED || sorted.Columns[0].Signature.Flags != 0 {
+		t.Errorf("unexpected signatures %v", sorted.Columns)
+	}
+	if r.MergeSort([]AttrInfo{keySig}, DESC).(Relation).Columns[1].Signature.Flags != 0 {
+		t.Error("descending column was flagged as sorted")
+	}
+
+	// the sorted column can still be referenced by its original signature
+	for _, comp := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+		for _, compVal := range []int{-1, 0, 50, 100, 101} {
+			if _, binarySearch, err := sorted.Columns[1].sortedRows(comp, compVal); !binarySearch || err != nil {
+				t.Errorf("(%v %d) is not evaluated using binary search", comp, compVal)
+			}
+
+			expected := []int{}
+			for _, key := range sorted.Columns[1].GetRawData().([]int) {
+				if compFuncs[INT][comp](key, compVal) {
+					expected = append(expected, key)
+				}
+			}
+
+			output, _ := sorted.Select(keySig, comp, compVal).GetRawData()
+			if !reflect.DeepEqual(output[1], expected) {
+				t.Errorf("selection (%v %d) does not match", comp, compVal)
+			}
+		}
+	}
+
+	// sorted columns only accept values in order
+	materialized := sorted.Materialize().(Relation)
+	if _, err := materialized.Columns[1].AddRow(INT, 100); err != nil {
+		t.Errorf("appending in order failed: %v", err)
+	}
+	if _, err := materialized.Columns[1].AddRow(INT, 99); err == nil {
+		t.Error("appending out of order succeeded")
+	}
+	if _, err := materialized.Columns[1].AddRows(INT, []int{101, 103, 102}); err == nil {
+		t.Error("appending unsorted rows succeeded")
+	}
+	if materialized.Columns[1].GetNumRows() != 501 {
+		t.Errorf("unexpected number of rows %d", materialized.Columns[1].GetNumRows())
+	}
+
+	// merge joins on sorted inputs (which aren't sorted again) match the joins on unsorted inputs
+	sortedByKey := r.MergeSort([]AttrInfo{keySig}, ASC).(Relation)
+	sortedKeySig := sortedByKey.Columns[1].Signature
+	expected, _ := r.MergeJoin([]AttrInfo{keySig}, r, []AttrInfo{keySig}, INNER, EQ).GetRawData()
+	output, _ := sortedByKey.MergeJoin([]AttrInfo{sortedKeySig}, sortedByKey, []AttrInfo{sortedKeySig}, INNER, EQ).GetRawData()
+	if !reflect.DeepEqual(output, expected) {
+		t.Error("merge join on sorted inputs does not match")
+	}
+}
+
+func TestRelationSortedColumnNaN(t *testing.T) {
+	sig := AttrInfo{"value", FLOAT, NOCOMP, 0}
+	r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(sig, []float64{2, math.NaN(), 1})}}
+	if r.MergeSort([]AttrInfo{sig}, ASC).(Relation).Columns[0].Signature.Flags&SORTED != 0 {
+		t.Error("column containing NaN was flagged as sorted")
+	}
+
+	// sorted columns reject NaN, even as their first value
+	sortedSig := AttrInfo{"value", FLOAT, NOCOMP, SORTED}
+	col := NewColumn(sortedSig)
+	if _, err := col.AddRow(FLOAT, math.NaN()); err == nil {
+		t.Error("adding NaN to an empty sorted column succeeded")
+	}
+	if _, err := col.AddRows(FLOAT, []float64{1, 2, 3}); err != nil {
+		t.Errorf("appending in order failed: %v", err)
+	}
+	if _, err := col.AddRows(FLOAT, []float64{4, math.NaN()}); err == nil {
+		t.Error("adding NaN to a sorted column succeeded")
+	}
+
+	// predicates on NaN can't use binary search and are evaluated by a scan instead
+	for comp, expected := range map[Comparison][]int{EQ: {}, LT: {}, GEQ: {}, NEQ: {0, 1, 2}} {
+		if _, binarySearch, _ := col.sortedRows(comp, math.NaN()); binarySearch {
+			t.Errorf("(%v NaN) is evaluated using binary search", comp)
+		}
+		rows, err := col.selectRows(comp, math.NaN(), 1)
+		if err != nil || !reflect.DeepEqual(rows, expected) {
+			t.Errorf("unexpected rows for (%v NaN): %v, %v", comp, rows, err)
+		}
+	}
+
+	// rows which can't be read are reported instead of panicking
+	view := newColumnView(sortedSig, &col, []int{0, 1, 5})
+	if _, err := view.selectRows(LT, 2.5, 1); err == nil {
+		t.Error("binary search over an unreadable row succeeded")
+	}
+}
+
 func TestRelationMergeJoin(t *testing.T) {
 	cases := []struct {
 		left      Relation
@@ -769,7 +1087,7 @@ func TestRelationMergeJoin(t *testing.T) {
 	}
 
 	for _, testCase := range cases {
-		output := testCase.left.MergeJoin(testCase.leftCols, testCase.right, testCase.rightCols, testCase.joinType, testCase.compType)
+		output := testCase.left.MergeJoin(testCase.leftCols, testCase.right, testCase.rightCols, testCase.joinType, testCase.compType).(Relation).Materialize()
 
 		if !reflect.DeepEqual(output, testCase.output) {
 			t.Fail()
@@ -777,3 +1095,45 @@ func TestRelationMergeJoin(t *testing.T) {
 		}
 	}
 }
+
+func TestRelationSortedDictionary(t *testing.T) {
+	createRelation := func(name string, enc Compression, values []string) Relation {
+		col := NewColumnWithData(AttrInfo{"col", STRING, enc, 0}, values)
+		if ds, isDict := col.Data.(*DictEncodedDataStore); isDict {
+			ds.SortDictionary()
+		}
+		return Relation{Name: name, Columns: []Column{col, NewColumnWithData(AttrInfo{"row", INT, NOCOMP, 0}, []int{0, 1, 2, 3, 4, 5})}}
+	}
+
+	leftValues := []string{"d", "b", "f", "a", "b", "c"}
+	rightValues := []string{"c", "e", "b", "g", "a", "c"}
+	cols := []AttrInfo{{"col", STRING, DICT, 0}}
+	plainCols := []AttrInfo{{"col", STRING, NOCOMP, 0}}
+
+	left, right := createRelation("left", DICT, leftValues), createRelation("right", DICT, rightValues)
+	plainLeft, plainRight := createRelation("left", NOCOMP, leftValues), createRelation("right", NOCOMP, rightValues)
+
+	for _, sortOrder := range []SortOrder{ASC, DESC} {
+		data, _ := left.MergeSort(cols, sortOrder).GetRawData()
+		expected, _ := plainLeft.MergeSort(plainCols, sortOrder).GetRawData()
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("sort order %v: expected %v, got %v", sortOrder, expected, data)
+		}
+	}
+
+	for _, compType := range []Comparison{EQ, LT, GEQ} {
+		data, _ := left.MergeJoin(cols, right, cols, INNER, compType).GetRawData()
+		expected, _ := plainLeft.MergeJoin(plainCols, plainRight, plainCols, INNER, compType).GetRawData()
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("comparison %v: expected %v, got %v", compType, expected, data)
+		}
+	}
+
+	for _, compType := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+		data, _ := left.Select(cols[0], compType, "c").GetRawData()
+		expected, _ := plainLeft.Select(plainCols[0], compType, "c").GetRawData()
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("select with %v: expected %v, got %v", compType, expected, data)
+		}
+	}
+}
@@ -1,23 +1,28 @@
 package csgo
 
-import "errors"
-
-// RLEDataEntry is an entry in a run length encoded column.
-type RLEDataEntry struct {
-	Count int
-	Value interface{}
-}
+import (
+	"errors"
+	"sort"
+)
 
 // RLEDataStore is a run length encoded DataStore
 type RLEDataStore struct {
 	DataType DataTypes
 	Flags    ColumnFlags
-	Entries  []RLEDataEntry
+	// Counts contains the number of rows of every run (entry).
+	Counts []int
+	// Values contains the value of every run. Ungrouped values are stored unboxed (see
+	// newValueStore).
+	Values DataStore
+
+	// ends contains the cumulative end offset (exclusive row index) of every entry. It is kept up
+	// to date by AddRow and AddRows, see runEnds for DataStores created by a struct literal.
+	ends []int
 }
 
 // NewRLEDataStore creates a new RLEDataStore
 func NewRLEDataStore(dataType DataTypes, flags ColumnFlags) DataStore {
-	return &RLEDataStore{dataType, flags, []RLEDataEntry{}}
+	return &RLEDataStore{DataType: dataType, Flags: flags, Counts: []int{}, Values: newValueStore(dataType, flags), ends: []int{}}
 }
 
 // GetDataType returns the type of the stored data.
@@ -64,36 +69,209 @@ func (ds *RLEDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
 		return -1, errors.New("type mismatch")
 	}
 
-	if len(ds.Entries) > 0 {
-		if ds.Entries[len(ds.Entries)-1].Value == value {
-			ds.Entries[len(ds.Entries)-1].Count++
-			return ds.GetNumRows() - 1, nil
-		}
+	ds.ends = ds.runEnds()
+	ds.addRun(value, 1)
+	return ds.GetNumRows() - 1, nil
+}
+
+// addRun adds count rows containing value. They extend the last entry if it contains the same
+// (ungrouped) value, otherwise a new entry is appended. ends has to be up to date.
+func (ds *RLEDataStore) addRun(value interface{}, count int) {
+	if ds.Values == nil {
+		ds.Values = newValueStore(ds.DataType, ds.Flags)
 	}
 
-	newEntry := RLEDataEntry{1, value}
-	ds.Entries = append(ds.Entries, newEntry)
-	return ds.GetNumRows() - 1, nil
+	// grouped values are slices and thus not comparable
+	if last := len(ds.Counts) - 1; last >= 0 && ds.Flags == 0 && valueAt(ds.Values, last) == value {
+		ds.Counts[last] += count
+		ds.ends[last] += count
+		return
+	}
+
+	ds.Values.AddRow(ds.DataType, value)
+	ds.ends = append(ds.ends, ds.GetNumRows()+count)
+	ds.Counts = append(ds.Counts, count)
+}
+
+// runEnds returns the cumulative end offsets of all entries. If ends doesn't match the entries
+// (e.g. for a DataStore created by a struct literal), they are computed without storing them, so
+// concurrent reads stay safe. The next AddRow or AddRows stores them.
+func (ds RLEDataStore) runEnds() []int {
+	if len(ds.ends) == len(ds.Counts) {
+		return ds.ends
+	}
+
+	ends := make([]int, len(ds.Counts))
+	end := 0
+	for entry, count := range ds.Counts {
+		end += count
+		ends[entry] = end
+	}
+	return ends
+}
+
+// findEntry returns the index of the entry containing the given row (binary search on the run ends).
+func (ds RLEDataStore) findEntry(rowIndex int) int {
+	ends := ds.runEnds()
+	return sort.Search(len(ends), func(entry int) bool { return ends[entry] > rowIndex })
 }
 
 // GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
 func (ds RLEDataStore) GetRow(rowIndex int) (interface{}, error) {
-	entryCount := 0
-	for _, entry := range ds.Entries {
-		entryCount += entry.Count
-		if entryCount > rowIndex {
-			return entry.Value, nil
-		}
+	if rowIndex < 0 || rowIndex >= ds.GetNumRows() {
+		return nil, errors.New("value not found")
 	}
 
-	return nil, errors.New("value not found")
+	return ds.Values.GetRow(ds.findEntry(rowIndex))
 }
 
 // GetNumRows returns the number of rows currently included in this column
 func (ds RLEDataStore) GetNumRows() int {
-	entryCount := 0
-	for _, entry := range ds.Entries {
-		entryCount += entry.Count
+	ends := ds.runEnds()
+	if len(ends) == 0 {
+		return 0
+	}
+	return ends[len(ends)-1]
+}
+
+// SelectRows appends the indices of all rows in [start, end) whose value satisfies the predicate
+// (value comp compVal) to out. The predicate is evaluated only once per run.
+func (ds *RLEDataStore) SelectRows(start int, end int, comp Comparison, compVal interface{}, out []int) ([]int, error) {
+	compFunc, err := checkPredicate(ds.DataType, ds.Flags, comp)
+	if err != nil {
+		return out, err
+	}
+
+	err = scanRuns(ds, start, end, func(runStart int, runEnd int, value interface{}) {
+		if compFunc(value, compVal) {
+			for row := runStart; row < runEnd; row++ {
+				out = append(out, row)
+			}
+		}
+	})
+	return out, err
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+// Consecutive equal values are appended as a single run.
+func (ds *RLEDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("invalid type")
+	}
+
+	ds.ends = ds.runEnds()
+	if ds.Flags == 0 {
+		switch typed := values.(type) {
+		case []int:
+			return addRuns(ds, INT, typed)
+		case []float64:
+			return addRuns(ds, FLOAT, typed)
+		case []string:
+			return addRuns(ds, STRING, typed)
+		}
+	}
+
+	// grouped values are slices and thus not comparable, every one gets its own entry
+	boxed, err := boxValues(ds.DataType, ds.Flags, values)
+	if err != nil {
+		return -1, err
+	}
+	firstIndex := ds.GetNumRows()
+	for _, value := range boxed {
+		ds.addRun(value, 1)
+	}
+	return firstIndex, nil
+}
+
+// addRuns adds the runs of consecutive equal values of the type typ to ds and returns the index of
+// the first added row. ends has to be up to date.
+func addRuns[T comparable](ds *RLEDataStore, typ DataTypes, values []T) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := ds.GetNumRows()
+	for runStart := 0; runStart < len(values); {
+		runEnd := runStart + 1
+		for runEnd < len(values) && values[runEnd] == values[runStart] {
+			runEnd++
+		}
+		ds.addRun(values[runStart], runEnd-runStart)
+		runStart = runEnd
+	}
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end).
+func (ds *RLEDataStore) GetRange(start int, end int) (interface{}, error) {
+	// the entry of every row, the values are then read from the typed run values at once
+	entries := make([]int, 0, max(end-start, 0))
+	err := ds.scanEntries(start, end, func(runStart int, runEnd int, entry int) {
+		for row := runStart; row < runEnd; row++ {
+			entries = append(entries, entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ds.Values == nil {
+		return unboxValues(ds.DataType, ds.Flags, []interface{}{})
+	}
+	return gatherValues(ds.Values, ds.DataType, ds.Flags, entries)
+}
+
+// scanEntries calls fn for every entry overlapping the rows [start, end), clipped to that range.
+func (ds *RLEDataStore) scanEntries(start int, end int, fn func(runStart int, runEnd int, entry int)) error {
+	if err := checkRange(start, end, ds.GetNumRows()); err != nil {
+		return err
+	}
+
+	ends := ds.runEnds()
+	for entry := ds.findEntry(start); entry < len(ends) && ends[entry]-ds.Counts[entry] < end; entry++ {
+		fn(max(start, ends[entry]-ds.Counts[entry]), min(end, ends[entry]), entry)
+	}
+	return nil
+}
+
+// RLECursor reads the rows of an RLEDataStore sequentially, advancing in O(1) per row.
+type RLECursor struct {
+	ds    *RLEDataStore
+	ends  []int
+	row   int
+	entry int
+}
+
+// Cursor creates an RLECursor positioned before the given row, i.e. the first call to Next moves
+// it onto rowIndex.
+func (ds *RLEDataStore) Cursor(rowIndex int) *RLECursor {
+	return &RLECursor{ds: ds, ends: ds.runEnds(), row: rowIndex - 1, entry: ds.findEntry(rowIndex - 1)}
+}
+
+// Next advances the cursor to the next row. It returns false after the last row.
+func (cursor *RLECursor) Next() bool {
+	cursor.row++
+	for cursor.entry < len(cursor.ends) && cursor.ends[cursor.entry] <= cursor.row {
+		cursor.entry++
+	}
+	return cursor.entry < len(cursor.ends)
+}
+
+// Value returns the value of the current row.
+func (cursor *RLECursor) Value() interface{} {
+	return valueAt(cursor.ds.Values, cursor.entry)
+}
+
+// Row returns the index of the current row.
+func (cursor *RLECursor) Row() int {
+	return cursor.row
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore, including the run
+// values.
+func (ds *RLEDataStore) MemoryUsage() int {
+	size := 2*wordBytes + 2*sliceHeaderBytes + interfaceBytes + cap(ds.Counts)*wordBytes + cap(ds.ends)*wordBytes
+	if ds.Values != nil {
+		size += ds.Values.MemoryUsage()
 	}
-	return entryCount
+	return size
 }
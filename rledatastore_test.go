@@ -1,13 +1,16 @@
 package csgo
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func createRLEDataStoreCases() []RLEDataStore {
 	return []RLEDataStore{
-		{INT, 0, []RLEDataEntry{{1, int(1)}, {1, int(2)}, {1, int(3)}}},
-		{FLOAT, 0, []RLEDataEntry{{1, float64(3.1)}, {1, float64(2.2)}, {1, float64(1.3)}}},
-		{STRING, 0, []RLEDataEntry{{1, "test1"}, {1, "arg2"}, {1, "test3"}}},
-		{INT, 0, []RLEDataEntry{}},
+		{INT, 0, []int{1, 1, 1}, fillDataStore(NewTypedDataStore(INT), int(1), int(2), int(3)), nil},
+		{FLOAT, 0, []int{1, 1, 1}, fillDataStore(NewTypedDataStore(FLOAT), float64(3.1), float64(2.2), float64(1.3)), nil},
+		{STRING, 0, []int{1, 1, 1}, fillDataStore(NewTypedDataStore(STRING), "test1", "arg2", "test3"), nil},
+		{INT, 0, []int{}, NewTypedDataStore(INT), nil},
 	}
 }
 
@@ -34,3 +37,73 @@ func TestRLEDataStoreGetDataType(t *testing.T) {
 		testDataStoreGetDataType(&ds, ds.DataType, t)
 	}
 }
+
+func TestRLEDataStoreSelectRows(t *testing.T) {
+	testDataStoreSelectRows(fillDataStore(NewRLEDataStore(INT, 0), 3, 3, 1, 2, 2, 2, 5, 3), 2, t)
+	testDataStoreSelectRows(fillDataStore(NewRLEDataStore(FLOAT, 0), 0.5, 1.5, 1.5, 1.5, 0.5, 0.5), 1.5, t)
+	testDataStoreSelectRows(fillDataStore(NewRLEDataStore(STRING, 0), "b", "b", "a", "c", "c", "a"), "b", t)
+}
+
+func TestRLEDataStoreAddRowsGetRange(t *testing.T) {
+	for _, ds := range createRLEDataStoreCases() {
+		switch ds.DataType {
+		case INT:
+			testDataStoreAddRowsGetRange(&ds, []int{3, 3, 3, 1, 1, 2}, t)
+		case FLOAT:
+			testDataStoreAddRowsGetRange(&ds, []float64{1.3, 1.3, 0.5}, t)
+		case STRING:
+			testDataStoreAddRowsGetRange(&ds, []string{"test3", "a", "b", "b"}, t)
+		}
+	}
+
+	// runs get merged with the last run and each other
+	ds := fillDataStore(NewRLEDataStore(INT, 0), 1, 2).(*RLEDataStore)
+	ds.AddRows(INT, []int{2, 2, 3, 3})
+	if !reflect.DeepEqual(ds.Counts, []int{1, 3, 2}) || !reflect.DeepEqual(ds.Values.(*TypedDataStore[int]).Values, []int{1, 2, 3}) || !reflect.DeepEqual(ds.ends, []int{1, 4, 6}) {
+		t.Errorf("unexpected runs %v of %v (ends %v)", ds.Counts, ds.Values, ds.ends)
+	}
+}
+
+func TestRLEDataStoreCursor(t *testing.T) {
+	values := []int{1, 1, 2, 3, 3, 3, 1}
+	ds := fillDataStore(NewRLEDataStore(INT, 0), 1, 1, 2, 3, 3, 3, 1).(*RLEDataStore)
+
+	for start := 0; start <= len(values); start++ {
+		output := []int{}
+		for cursor := ds.Cursor(start); cursor.Next(); {
+			if cursor.Row() != start+len(output) {
+				t.Errorf("start %d: expected row %d, got %d", start, start+len(output), cursor.Row())
+			}
+			output = append(output, cursor.Value().(int))
+		}
+
+		if !reflect.DeepEqual(output, values[start:]) {
+			t.Errorf("start %d: expected %v, got %v", start, values[start:], output)
+		}
+	}
+}
+
+func TestRLEDataStoreTypedValues(t *testing.T) {
+	ds := NewRLEDataStore(FLOAT, 0).(*RLEDataStore)
+	ds.AddRows(FLOAT, []float64{0.5, 0.5, 1.5})
+	ds.AddRow(FLOAT, 1.5)
+	if values, isTyped := ds.Values.(*TypedDataStore[float64]); !isTyped || !reflect.DeepEqual(values.Values, []float64{0.5, 1.5}) {
+		t.Errorf("run values are not stored unboxed: %#v", ds.Values)
+	}
+	if _, err := ds.AddRows(FLOAT, []int{1}); err == nil || ds.GetNumRows() != 4 {
+		t.Error("adding values of the wrong type succeeded")
+	}
+
+	// grouped values aren't comparable, so every group gets its own run
+	groups := [][]string{{"a"}, {"a"}, {}, {"b", "c"}}
+	grouped := NewRLEDataStore(STRING, GROUPED).(*RLEDataStore)
+	if _, err := grouped.AddRows(STRING, groups[:2]); err != nil {
+		t.Errorf("adding groups failed: %v", err)
+	}
+	for _, group := range groups[2:] {
+		grouped.AddRow(STRING, group)
+	}
+	if data, _ := grouped.GetRange(0, grouped.GetNumRows()); !reflect.DeepEqual(data, groups) || len(grouped.Counts) != 4 {
+		t.Errorf("expected %v, got %v", groups, data)
+	}
+}
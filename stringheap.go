@@ -0,0 +1,103 @@
+package csgo
+
+import "errors"
+
+// StringHeapDataStore is a DataStore for ungrouped STRING data storing all strings in a single
+// byte buffer. Only the end offsets of the strings are kept per row, so large text columns don't
+// consist of millions of small allocations the garbage collector has to track.
+type StringHeapDataStore struct {
+	// Heap contains the bytes of all strings.
+	Heap []byte
+	// Ends contains the end offset (exclusive) of every string within Heap.
+	Ends []int
+}
+
+// NewStringHeapDataStore creates a new StringHeapDataStore.
+func NewStringHeapDataStore() DataStore {
+	return &StringHeapDataStore{Heap: []byte{}, Ends: []int{}}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *StringHeapDataStore) GetDataType() DataTypes {
+	return STRING
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *StringHeapDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// AddRow adds a new row to the column.
+func (ds *StringHeapDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != STRING {
+		return -1, errors.New("invalid type")
+	}
+
+	str, rightType := value.(string)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	ds.Heap = append(ds.Heap, str...)
+	ds.Ends = append(ds.Ends, len(ds.Heap))
+	return len(ds.Ends) - 1, nil
+}
+
+// get returns the string of the given row.
+func (ds *StringHeapDataStore) get(rowIndex int) string {
+	start := 0
+	if rowIndex > 0 {
+		start = ds.Ends[rowIndex-1]
+	}
+	return string(ds.Heap[start:ds.Ends[rowIndex]])
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *StringHeapDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= len(ds.Ends) {
+		return nil, errors.New("index out of bounds")
+	}
+	return ds.get(rowIndex), nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *StringHeapDataStore) GetNumRows() int {
+	return len(ds.Ends)
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+func (ds *StringHeapDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != STRING {
+		return -1, errors.New("invalid type")
+	}
+
+	strings, rightType := values.([]string)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := len(ds.Ends)
+	for _, str := range strings {
+		ds.Heap = append(ds.Heap, str...)
+		ds.Ends = append(ds.Ends, len(ds.Heap))
+	}
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end).
+func (ds *StringHeapDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, len(ds.Ends)); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, end-start)
+	for i := range values {
+		values[i] = ds.get(start + i)
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *StringHeapDataStore) MemoryUsage() int {
+	return 2*sliceHeaderBytes + cap(ds.Heap) + cap(ds.Ends)*wordBytes
+}
@@ -0,0 +1,51 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createStringHeapDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewStringHeapDataStore(), "a", "", "abc", "b"),
+		fillDataStore(NewStringHeapDataStore(), "", ""),
+		NewStringHeapDataStore(),
+	}
+}
+
+func TestStringHeapDataStoreAddRow(t *testing.T) {
+	for _, ds := range createStringHeapDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestStringHeapDataStoreGetRow(t *testing.T) {
+	for _, ds := range createStringHeapDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestStringHeapDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createStringHeapDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestStringHeapDataStoreAddRowsGetRange(t *testing.T) {
+	values := []string{"furiously", "", "regular", "deposits", "", "x"}
+
+	for _, ds := range createStringHeapDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	ds := NewStringHeapDataStore().(*StringHeapDataStore)
+	ds.AddRows(STRING, values)
+	if string(ds.Heap) != "furiouslyregulardepositsx" || !reflect.DeepEqual(ds.Ends, []int{9, 9, 16, 24, 24, 25}) {
+		t.Errorf("unexpected heap %q with ends %v", ds.Heap, ds.Ends)
+	}
+
+	col := NewColumnWithData(AttrInfo{"col", STRING, STRINGHEAP, 0}, values)
+	if _, isHeap := col.Data.(*StringHeapDataStore); !isHeap || !reflect.DeepEqual(col.GetRawData(), values) {
+		t.Error("string heap column does not match")
+	}
+}
@@ -0,0 +1,130 @@
+package csgo
+
+import "errors"
+
+// TypedDataStore is an uncompressed DataStore for ungrouped data. In contrast to BasicDataStore the
+// values are stored in a slice of the matching Go type ([]int, []float64 or []string), so they
+// don't need to be boxed.
+// NewColumn uses it for ungrouped NOCOMP columns and the codes of dictionaries. It also keeps the
+// ungrouped run values of RLEDataStore and the dictionary of DictEncodedDataStore (see
+// newValueStore).
+type TypedDataStore[T ordered] struct {
+	// DataType represents the type of the stored data
+	DataType DataTypes
+	// Values contains the data of this column.
+	Values []T
+}
+
+// NewTypedDataStore creates a new TypedDataStore for the given data type.
+func NewTypedDataStore(dataType DataTypes) DataStore {
+	switch dataType {
+	case INT:
+		return &TypedDataStore[int]{DataType: INT, Values: []int{}}
+	case FLOAT:
+		return &TypedDataStore[float64]{DataType: FLOAT, Values: []float64{}}
+	case STRING:
+		return &TypedDataStore[string]{DataType: STRING, Values: []string{}}
+	}
+	return NewBasicDataStore(dataType, 0)
+}
+
+// GetDataType returns the type of the values in the DataStore
+func (ds *TypedDataStore[T]) GetDataType() DataTypes {
+	return ds.DataType
+}
+
+// GetFlags returns the flags for the values in the DataStore
+func (ds *TypedDataStore[T]) GetFlags() ColumnFlags {
+	return 0
+}
+
+// AddRow adds a row to the DataStore.
+func (ds *TypedDataStore[T]) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("invalid data type")
+	}
+
+	typedValue, rightType := value.(T)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	ds.Values = append(ds.Values, typedValue)
+	return len(ds.Values) - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *TypedDataStore[T]) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= len(ds.Values) {
+		return nil, errors.New("index out of bounds")
+	}
+	return ds.Values[rowIndex], nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *TypedDataStore[T]) GetNumRows() int {
+	return len(ds.Values)
+}
+
+// SelectRows appends the indices of all rows in [start, end) whose value satisfies the predicate
+// (value comp compVal) to out.
+func (ds *TypedDataStore[T]) SelectRows(start int, end int, comp Comparison, compVal interface{}, out []int) ([]int, error) {
+	if _, err := checkPredicate(ds.DataType, 0, comp); err != nil {
+		return out, err
+	}
+
+	if start < 0 || end > len(ds.Values) || start > end {
+		return out, errors.New("index out of bounds")
+	}
+
+	typedCompVal, rightType := compVal.(T)
+	if !rightType {
+		return out, errors.New("type mismatch")
+	}
+
+	// selectOrdered returns positions relative to start
+	numMatches := len(out)
+	out = selectOrdered(ds.Values[start:end], comp, typedCompVal, nil, out)
+	for i := numMatches; i < len(out); i++ {
+		out[i] += start
+	}
+	return out, nil
+}
+
+// AddRows adds all values to the DataStore and returns the index of the first added row.
+func (ds *TypedDataStore[T]) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != ds.DataType {
+		return -1, errors.New("invalid data type")
+	}
+
+	typedValues, rightType := values.([]T)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := len(ds.Values)
+	ds.Values = append(ds.Values, typedValues...)
+	return firstIndex, nil
+}
+
+// GetRange returns a copy of the values of the rows [start, end).
+func (ds *TypedDataStore[T]) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, len(ds.Values)); err != nil {
+		return nil, err
+	}
+	return append([]T{}, ds.Values[start:end]...), nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *TypedDataStore[T]) MemoryUsage() int {
+	size := wordBytes + sliceHeaderBytes
+	if ds.DataType != STRING {
+		return size + cap(ds.Values)*wordBytes
+	}
+
+	size += (cap(ds.Values) - len(ds.Values)) * stringHeaderBytes
+	for _, value := range ds.Values {
+		size += valueBytes(value)
+	}
+	return size
+}
@@ -0,0 +1,55 @@
+package csgo
+
+import "testing"
+
+func createTypedDataStoreCases() []DataStore {
+	return []DataStore{
+		&TypedDataStore[int]{INT, []int{1, 2, 3}},
+		&TypedDataStore[float64]{FLOAT, []float64{3.1, 2.2, 1.3}},
+		&TypedDataStore[string]{STRING, []string{"test1", "arg2", "test3"}},
+		NewTypedDataStore(INT),
+	}
+}
+
+func TestTypedDataStoreAddRow(t *testing.T) {
+	for _, ds := range createTypedDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestTypedDataStoreGetRow(t *testing.T) {
+	for _, ds := range createTypedDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestTypedDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createTypedDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestTypedDataStoreGetDataType(t *testing.T) {
+	for _, ds := range createTypedDataStoreCases() {
+		testDataStoreGetDataType(ds, ds.GetDataType(), t)
+	}
+}
+
+func TestTypedDataStoreSelectRows(t *testing.T) {
+	testDataStoreSelectRows(fillDataStore(NewTypedDataStore(INT), 3, 3, 1, 2, 2, 2, 5, 3), 2, t)
+	testDataStoreSelectRows(fillDataStore(NewTypedDataStore(FLOAT), 0.5, 1.5, 1.5, 1.5, 0.5, 0.5), 1.5, t)
+	testDataStoreSelectRows(fillDataStore(NewTypedDataStore(STRING), "b", "b", "a", "c", "c", "a"), "b", t)
+}
+
+func TestTypedDataStoreAddRowsGetRange(t *testing.T) {
+	for _, ds := range createTypedDataStoreCases() {
+		switch ds.GetDataType() {
+		case INT:
+			testDataStoreAddRowsGetRange(ds, []int{1, 2, 2, 3}, t)
+		case FLOAT:
+			testDataStoreAddRowsGetRange(ds, []float64{0.5, 1.5}, t)
+		case STRING:
+			testDataStoreAddRowsGetRange(ds, []string{"a", "b", "b"}, t)
+		}
+	}
+}
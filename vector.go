@@ -0,0 +1,382 @@
+package csgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchSize is the number of rows processed at once by the vectorized operators.
+const BatchSize = 1024
+
+// ordered is the set of all Go types backing the (ungrouped) column data types.
+type ordered interface {
+	~int | ~float64 | ~string
+}
+
+// Vector is a batch of consecutive values of a single (ungrouped) column in decompressed form.
+// Only the slice matching Type is used.
+type Vector struct {
+	// Type is the data type of the values.
+	Type DataTypes
+	// Ints contains the values of an INT column.
+	Ints []int
+	// Floats contains the values of a FLOAT column.
+	Floats []float64
+	// Strings contains the values of a STRING column.
+	Strings []string
+}
+
+// Len returns the number of values in the vector.
+func (vec *Vector) Len() int {
+	switch vec.Type {
+	case INT:
+		return len(vec.Ints)
+	case FLOAT:
+		return len(vec.Floats)
+	case STRING:
+		return len(vec.Strings)
+	}
+	return 0
+}
+
+// reset empties the vector (keeping the allocated memory) and sets its type.
+func (vec *Vector) reset(typ DataTypes) {
+	vec.Type = typ
+	vec.Ints = vec.Ints[:0]
+	vec.Floats = vec.Floats[:0]
+	vec.Strings = vec.Strings[:0]
+}
+
+// append adds a single value to the vector.
+func (vec *Vector) append(value interface{}) {
+	switch vec.Type {
+	case INT:
+		vec.Ints = append(vec.Ints, value.(int))
+	case FLOAT:
+		vec.Floats = append(vec.Floats, value.(float64))
+	case STRING:
+		vec.Strings = append(vec.Strings, value.(string))
+	}
+}
+
+// Batch is a set of vectors covering the same rows of a relation plus a selection vector.
+type Batch struct {
+	// Start is the row index of the first row of the batch.
+	Start int
+	// Vectors contains one vector per column.
+	Vectors []Vector
+	// Selection lists the positions (relative to Start) of all active rows in ascending order. A
+	// nil Selection means that all rows of the batch are active.
+	Selection []int
+}
+
+// Len returns the number of active rows of the batch.
+func (batch *Batch) Len() int {
+	if batch.Selection != nil {
+		return len(batch.Selection)
+	}
+	if len(batch.Vectors) == 0 {
+		return 0
+	}
+	return batch.Vectors[0].Len()
+}
+
+// Filter narrows the selection vector of the batch to the rows whose value in the vector vecIndex
+// satisfies the predicate (value comp compVal).
+func (batch *Batch) Filter(vecIndex int, comp Comparison, compVal interface{}) {
+	batch.Selection = selectVector(&batch.Vectors[vecIndex], comp, compVal, batch.Selection, make([]int, 0, batch.Len()))
+}
+
+// readVector reads the values of the rows [start, end) of col into vec.
+// Typed data is copied directly and run length encoded data is expanded run by run, any other
+// data is read via GetRange.
+func readVector(col *Column, start int, end int, vec *Vector) error {
+	vec.reset(col.Signature.Type)
+
+	if col.Signature.Flags&GROUPED != 0 {
+		return fmt.Errorf("grouped column %s can not be read into a vector", col.Signature.Name)
+	}
+
+	if start < 0 || end > col.GetNumRows() || start > end {
+		return fmt.Errorf("rows [%d, %d) out of bounds", start, end)
+	}
+
+	switch ds := col.Data.(type) {
+	case *TypedDataStore[int]:
+		vec.Ints = append(vec.Ints, ds.Values[start:end]...)
+		return nil
+	case *TypedDataStore[float64]:
+		vec.Floats = append(vec.Floats, ds.Values[start:end]...)
+		return nil
+	case *TypedDataStore[string]:
+		vec.Strings = append(vec.Strings, ds.Values[start:end]...)
+		return nil
+	case *RLEDataStore:
+		return scanRuns(ds, start, end, func(runStart int, runEnd int, value interface{}) {
+			for row := runStart; row < runEnd; row++ {
+				vec.append(value)
+			}
+		})
+	}
+
+	values, err := col.GetRange(start, end)
+	if err != nil {
+		return err
+	}
+
+	switch typed := values.(type) {
+	case []int:
+		vec.Ints = append(vec.Ints, typed...)
+	case []float64:
+		vec.Floats = append(vec.Floats, typed...)
+	case []string:
+		vec.Strings = append(vec.Strings, typed...)
+	}
+	return nil
+}
+
+// selectOrdered appends all positions of sel (or all positions of values if sel is nil) whose
+// value satisfies the predicate (value comp compVal) to out.
+func selectOrdered[T ordered](values []T, comp Comparison, compVal T, sel []int, out []int) []int {
+	if sel == nil {
+		sel = make([]int, len(values))
+		for i := range sel {
+			sel[i] = i
+		}
+	}
+
+	switch comp {
+	case EQ:
+		for _, i := range sel {
+			if values[i] == compVal {
+				out = append(out, i)
+			}
+		}
+	case NEQ:
+		for _, i := range sel {
+			if values[i] != compVal {
+				out = append(out, i)
+			}
+		}
+	case LT:
+		for _, i := range sel {
+			if values[i] < compVal {
+				out = append(out, i)
+			}
+		}
+	case LEQ:
+		for _, i := range sel {
+			if values[i] <= compVal {
+				out = append(out, i)
+			}
+		}
+	case GT:
+		for _, i := range sel {
+			if values[i] > compVal {
+				out = append(out, i)
+			}
+		}
+	case GEQ:
+		for _, i := range sel {
+			if values[i] >= compVal {
+				out = append(out, i)
+			}
+		}
+	default:
+		panic("comparison func not found")
+	}
+
+	return out
+}
+
+// selectSet appends all positions of sel (or all positions of values if sel is nil) whose value is
+// contained in set to out.
+func selectSet[T ordered](values []T, set map[T]struct{}, sel []int, out []int) []int {
+	if sel == nil {
+		for i, value := range values {
+			if _, found := set[value]; found {
+				out = append(out, i)
+			}
+		}
+		return out
+	}
+
+	for _, i := range sel {
+		if _, found := set[values[i]]; found {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// newSet creates a set containing all values.
+func newSet[T ordered](values []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
+
+// newValueSet converts the constant of an IN predicate (a slice of the column type) into the set
+// (map[T]struct{}) expected by selectVector and the zone maps.
+func newValueSet(typ DataTypes, compVal interface{}) (interface{}, error) {
+	switch values := compVal.(type) {
+	case []int:
+		if typ == INT {
+			return newSet(values), nil
+		}
+	case []float64:
+		if typ == FLOAT {
+			return newSet(values), nil
+		}
+	case []string:
+		if typ == STRING {
+			return newSet(values), nil
+		}
+	}
+	return nil, errors.New("type mismatch")
+}
+
+// selectVector is the type dispatching wrapper of selectOrdered. The constant of an IN predicate
+// has to be a set created by newValueSet.
+func selectVector(vec *Vector, comp Comparison, compVal interface{}, sel []int, out []int) []int {
+	if comp == IN {
+		switch set := compVal.(type) {
+		case map[int]struct{}:
+			return selectSet(vec.Ints, set, sel, out)
+		case map[float64]struct{}:
+			return selectSet(vec.Floats, set, sel, out)
+		case map[string]struct{}:
+			return selectSet(vec.Strings, set, sel, out)
+		}
+		panic("unknown type")
+	}
+
+	switch vec.Type {
+	case INT:
+		return selectOrdered(vec.Ints, comp, compVal.(int), sel, out)
+	case FLOAT:
+		return selectOrdered(vec.Floats, comp, compVal.(float64), sel, out)
+	case STRING:
+		return selectOrdered(vec.Strings, comp, compVal.(string), sel, out)
+	}
+	panic("unknown type")
+}
+
+// hashVector combines the hashes of all values of vec into hashes (see hashValue).
+func hashVector(vec *Vector, hashes []uint64) {
+	switch vec.Type {
+	case INT:
+		for i, value := range vec.Ints {
+			hashes[i] = hashes[i]*31 + hashInt(value)
+		}
+	case FLOAT:
+		for i, value := range vec.Floats {
+			hashes[i] = hashes[i]*31 + hashFloat(value)
+		}
+	case STRING:
+		for i, value := range vec.Strings {
+			hashes[i] = hashes[i]*31 + hashString(value)
+		}
+	}
+}
+
+// aggregateOrdered applies a MIN, MAX or SUM aggregation to a non-empty slice of values.
+// SUM is not defined for strings and is handled by the caller.
+func aggregateOrdered[T ordered](values []T, aggrFunc AggrFunc) T {
+	aggrValue := values[0]
+
+	switch aggrFunc {
+	case SUM:
+		for _, value := range values[1:] {
+			aggrValue += value
+		}
+	case MIN:
+		for _, value := range values[1:] {
+			if value < aggrValue {
+				aggrValue = value
+			}
+		}
+	case MAX:
+		for _, value := range values[1:] {
+			if value > aggrValue {
+				aggrValue = value
+			}
+		}
+	}
+
+	return aggrValue
+}
+
+// aggregateGroupSlice applies aggrFunc to every group of groups. COUNT returns a []int, all other
+// functions return a []T.
+func aggregateGroupSlice[T ordered](groups [][]T, aggrFunc AggrFunc) interface{} {
+	if aggrFunc == COUNT {
+		counts := make([]int, len(groups))
+		for i, group := range groups {
+			counts[i] = len(group)
+		}
+		return counts
+	}
+
+	aggrValues := make([]T, len(groups))
+	for i, group := range groups {
+		aggrValues[i] = aggregateOrdered(group, aggrFunc)
+	}
+	return aggrValues
+}
+
+// aggregateGroups is the type dispatching wrapper of aggregateGroupSlice for a batch of groups as
+// returned by GetRange of a grouped column.
+func aggregateGroups(groups interface{}, aggrFunc AggrFunc) interface{} {
+	switch typed := groups.(type) {
+	case [][]int:
+		return aggregateGroupSlice(typed, aggrFunc)
+	case [][]float64:
+		return aggregateGroupSlice(typed, aggrFunc)
+	case [][]string:
+		return aggregateGroupSlice(typed, aggrFunc)
+	}
+	panic("unknown type")
+}
+
+// ScanBatches reads the specified columns of the relation batch by batch and calls fn for every
+// batch. The vectors of the batch are ordered like the columns of the relation.
+// The batch gets reused for the next call of fn, so fn must not keep references to it.
+func (r Relation) ScanBatches(colList []AttrInfo, fn func(batch *Batch)) {
+	cols := []*Column{}
+
+	for colIndex, col := range r.Columns {
+		for _, colHeader := range colList {
+			if col.Signature.matches(colHeader) {
+				cols = append(cols, &r.Columns[colIndex])
+				break
+			}
+		}
+	}
+
+	if len(cols) == 0 {
+		return
+	}
+
+	batch := Batch{Vectors: make([]Vector, len(cols))}
+	numRows := cols[0].GetNumRows()
+
+	for start := 0; start < numRows; start += BatchSize {
+		end := start + BatchSize
+		if end > numRows {
+			end = numRows
+		}
+
+		batch.Start = start
+		batch.Selection = nil
+		for colIndex, col := range cols {
+			if err := readVector(col, start, end, &batch.Vectors[colIndex]); err != nil {
+				panic(err)
+			}
+		}
+
+		fn(&batch)
+	}
+}
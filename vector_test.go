@@ -0,0 +1,118 @@
+package csgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadVector(t *testing.T) {
+	data := []int{1, 1, 1, 2, 2, 3, 4, 4, 4, 4, 5}
+
+	for _, encoding := range []Compression{NOCOMP, RLE, DICT} {
+		col := NewColumnWithData(AttrInfo{"intCol", INT, encoding, 0}, data)
+		vec := Vector{}
+
+		for start := 0; start <= len(data); start++ {
+			for end := start; end <= len(data); end++ {
+				if err := readVector(&col, start, end, &vec); err != nil {
+					t.Errorf("encoding %d: unexpected error %v", encoding, err)
+					continue
+				}
+				if !reflect.DeepEqual(append([]int{}, vec.Ints...), append([]int{}, data[start:end]...)) {
+					t.Errorf("encoding %d: rows [%d, %d) read as %v", encoding, start, end, vec.Ints)
+				}
+			}
+		}
+
+		if readVector(&col, 5, len(data)+1, &vec) == nil {
+			t.Errorf("encoding %d: reading out of bounds succeeded", encoding)
+		}
+	}
+}
+
+func TestSelectVector(t *testing.T) {
+	vec := Vector{Type: FLOAT, Floats: []float64{0.5, 1.5, 2.5, 1.5, 0.0}}
+
+	cases := []struct {
+		comp     Comparison
+		value    float64
+		sel      []int
+		expected []int
+	}{
+		{comp: EQ, value: 1.5, sel: nil, expected: []int{1, 3}},
+		{comp: NEQ, value: 1.5, sel: nil, expected: []int{0, 2, 4}},
+		{comp: LT, value: 1.5, sel: nil, expected: []int{0, 4}},
+		{comp: LEQ, value: 1.5, sel: []int{0, 1, 2}, expected: []int{0, 1}},
+		{comp: GT, value: 0.5, sel: []int{2, 3, 4}, expected: []int{2, 3}},
+		{comp: GEQ, value: 0.5, sel: []int{}, expected: []int{}},
+	}
+
+	for testcaseID, testcase := range cases {
+		result := selectVector(&vec, testcase.comp, testcase.value, testcase.sel, []int{})
+
+		if !reflect.DeepEqual(result, testcase.expected) {
+			t.Errorf("testcase %d: expected %v, got %v", testcaseID, testcase.expected, result)
+		}
+	}
+}
+
+func TestAggregateGroups(t *testing.T) {
+	cases := []struct {
+		groups   interface{}
+		aggrFunc AggrFunc
+		expected interface{}
+	}{
+		{groups: [][]int{{1, 2, 3}, {4}}, aggrFunc: SUM, expected: []int{6, 4}},
+		{groups: [][]int{{1, 2, 3}, {4}}, aggrFunc: COUNT, expected: []int{3, 1}},
+		{groups: [][]float64{{1.5, 0.5}, {2.5}}, aggrFunc: MIN, expected: []float64{0.5, 2.5}},
+		{groups: [][]string{{"b", "c", "a"}, {"d"}}, aggrFunc: MAX, expected: []string{"c", "d"}},
+		{groups: [][]string{{"b", "c", "a"}}, aggrFunc: COUNT, expected: []int{3}},
+	}
+
+	for testcaseID, testcase := range cases {
+		result := aggregateGroups(testcase.groups, testcase.aggrFunc)
+
+		if !reflect.DeepEqual(result, testcase.expected) {
+			t.Errorf("testcase %d: expected %v, got %v", testcaseID, testcase.expected, result)
+		}
+	}
+}
+
+func TestRelationScanBatches(t *testing.T) {
+	ints := []int{}
+	strs := []string{}
+	for i := 0; i < 3*BatchSize+17; i++ {
+		ints = append(ints, i)
+		strs = append(strs, string(rune('a'+i%3)))
+	}
+
+	r := Relation{Name: "testRel", Columns: []Column{
+		NewColumnWithData(AttrInfo{"intCol", INT, NOCOMP, 0}, ints),
+		NewColumnWithData(AttrInfo{"strCol", STRING, RLE, 0}, strs),
+	}}
+
+	selected := []int{}
+	r.ScanBatches([]AttrInfo{{"intCol", INT, NOCOMP, 0}, {"strCol", STRING, RLE, 0}}, func(batch *Batch) {
+		if len(batch.Vectors) != 2 || batch.Vectors[0].Len() != batch.Vectors[1].Len() {
+			t.Errorf("batch at row %d has unexpected vectors", batch.Start)
+			return
+		}
+
+		batch.Filter(1, EQ, "b")
+		batch.Filter(0, GEQ, 2*BatchSize)
+		for _, position := range batch.Selection {
+			selected = append(selected, batch.Vectors[0].Ints[position])
+		}
+	})
+
+	expected := []int{}
+	for i := 2 * BatchSize; i < len(ints); i++ {
+		if strs[i] == "b" {
+			expected = append(expected, i)
+		}
+	}
+
+	if !reflect.DeepEqual(selected, expected) {
+		t.Errorf("expected %d selected rows, got %d", len(expected), len(selected))
+	}
+}
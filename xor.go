@@ -0,0 +1,208 @@
+package csgo
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// XORBlockSize is the number of rows between two checkpoints of an XORDataStore.
+const XORBlockSize = 128
+
+// XORDataStore is a DataStore for ungrouped FLOAT data using the XOR compression of the Gorilla
+// time series database. Every value is XORed with its predecessor and only the bits between the
+// leading and trailing zeros of the result are stored:
+//
+//	'0'                          the value equals its predecessor
+//	'10' + bits                  the bits fit into the window of the previous value
+//	'11' + 6 bits leading zeros
+//	     + 6 bits length-1 + bits a new window is used
+//
+// The first value of every block is stored uncompressed, so random access only has to decode the
+// values of a single block.
+type XORDataStore struct {
+	// Checkpoints contains the bit position of the first value of every block.
+	Checkpoints []int
+	// Bits contains the encoded values.
+	Bits BitStream
+	// Last, Leading and Trailing describe the last value and its window (needed for appending).
+	Last     uint64
+	Leading  int
+	Trailing int
+	NumRows  int
+}
+
+// NewXORDataStore creates a new XORDataStore.
+func NewXORDataStore() DataStore {
+	return &XORDataStore{Checkpoints: []int{}}
+}
+
+// GetDataType returns the type of the stored data.
+func (ds *XORDataStore) GetDataType() DataTypes {
+	return FLOAT
+}
+
+// GetFlags returns the flags for the stored data
+func (ds *XORDataStore) GetFlags() ColumnFlags {
+	return 0
+}
+
+// appendValue encodes value and adds it to the bit stream.
+func (ds *XORDataStore) appendValue(value float64) {
+	valueBits := math.Float64bits(value)
+
+	if ds.NumRows%XORBlockSize == 0 {
+		ds.Checkpoints = append(ds.Checkpoints, ds.Bits.NumBits)
+		ds.Bits.Write(valueBits, 64)
+		ds.Last, ds.Leading, ds.Trailing = valueBits, -1, -1
+		ds.NumRows++
+		return
+	}
+
+	xor := valueBits ^ ds.Last
+	ds.Last = valueBits
+	ds.NumRows++
+
+	if xor == 0 {
+		ds.Bits.Write(0, 1)
+		return
+	}
+
+	leading, trailing := bits.LeadingZeros64(xor), bits.TrailingZeros64(xor)
+	if ds.Leading >= 0 && leading >= ds.Leading && trailing >= ds.Trailing {
+		ds.Bits.Write(0b01, 2)
+		ds.Bits.Write(xor>>uint(ds.Trailing), 64-ds.Leading-ds.Trailing)
+		return
+	}
+
+	length := 64 - leading - trailing
+	ds.Bits.Write(0b11, 2)
+	ds.Bits.Write(uint64(leading), 6)
+	ds.Bits.Write(uint64(length-1), 6)
+	ds.Bits.Write(xor>>uint(trailing), length)
+	ds.Leading, ds.Trailing = leading, trailing
+}
+
+// xorReader decodes the values of an XORDataStore sequentially.
+type xorReader struct {
+	ds       *XORDataStore
+	row      int
+	pos      int
+	value    uint64
+	leading  int
+	trailing int
+}
+
+// newXORReader creates an xorReader positioned at the beginning of the block containing rowIndex.
+func newXORReader(ds *XORDataStore, rowIndex int) *xorReader {
+	block := rowIndex / XORBlockSize
+	return &xorReader{ds: ds, row: block * XORBlockSize, pos: ds.Checkpoints[block]}
+}
+
+// next decodes the value of the current row and advances to the next row.
+func (reader *xorReader) next() float64 {
+	stream := &reader.ds.Bits
+
+	switch {
+	case reader.row%XORBlockSize == 0:
+		reader.value = stream.Read(reader.pos, 64)
+		reader.pos += 64
+	case stream.Read(reader.pos, 1) == 0:
+		reader.pos++
+	default:
+		// the control bits are written as '1' followed by the window flag
+		newWindow := stream.Read(reader.pos+1, 1) == 1
+		reader.pos += 2
+
+		if newWindow {
+			reader.leading = int(stream.Read(reader.pos, 6))
+			length := int(stream.Read(reader.pos+6, 6)) + 1
+			reader.trailing = 64 - reader.leading - length
+			reader.pos += 12
+		}
+
+		length := 64 - reader.leading - reader.trailing
+		reader.value ^= stream.Read(reader.pos, length) << uint(reader.trailing)
+		reader.pos += length
+	}
+
+	reader.row++
+	return math.Float64frombits(reader.value)
+}
+
+// AddRow adds a new row to the column.
+func (ds *XORDataStore) AddRow(typ DataTypes, value interface{}) (int, error) {
+	if typ != FLOAT {
+		return -1, errors.New("invalid type")
+	}
+
+	floatValue, rightType := value.(float64)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	ds.appendValue(floatValue)
+	return ds.NumRows - 1, nil
+}
+
+// GetRow returns the value at the indicated row. If that value can not be found, an error is returned.
+func (ds *XORDataStore) GetRow(rowIndex int) (interface{}, error) {
+	if rowIndex < 0 || rowIndex >= ds.NumRows {
+		return nil, errors.New("index out of bounds")
+	}
+
+	reader := newXORReader(ds, rowIndex)
+	for reader.row < rowIndex {
+		reader.next()
+	}
+	return reader.next(), nil
+}
+
+// GetNumRows returns the number of rows currently included in this column
+func (ds *XORDataStore) GetNumRows() int {
+	return ds.NumRows
+}
+
+// AddRows adds all values to the column and returns the index of the first added row.
+func (ds *XORDataStore) AddRows(typ DataTypes, values interface{}) (int, error) {
+	if typ != FLOAT {
+		return -1, errors.New("invalid type")
+	}
+
+	floatValues, rightType := values.([]float64)
+	if !rightType {
+		return -1, errors.New("type mismatch")
+	}
+
+	firstIndex := ds.NumRows
+	for _, value := range floatValues {
+		ds.appendValue(value)
+	}
+	return firstIndex, nil
+}
+
+// GetRange returns the values of the rows [start, end), which are decoded sequentially.
+func (ds *XORDataStore) GetRange(start int, end int) (interface{}, error) {
+	if err := checkRange(start, end, ds.NumRows); err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, end-start)
+	if start == end {
+		return values, nil
+	}
+
+	reader := newXORReader(ds, start)
+	for reader.row < start {
+		reader.next()
+	}
+	for reader.row < end {
+		values = append(values, reader.next())
+	}
+	return values, nil
+}
+
+// MemoryUsage returns the estimated number of bytes used by the DataStore.
+func (ds *XORDataStore) MemoryUsage() int {
+	return sliceHeaderBytes + cap(ds.Checkpoints)*wordBytes + 4*wordBytes + ds.Bits.memoryUsage()
+}
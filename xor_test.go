@@ -0,0 +1,64 @@
+package csgo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func createXORDataStoreCases() []DataStore {
+	return []DataStore{
+		fillDataStore(NewXORDataStore(), 3.1, 2.2, 1.3),
+		fillDataStore(NewXORDataStore(), 0.0, math.Copysign(0, -1), math.Inf(1), -math.MaxFloat64, math.SmallestNonzeroFloat64),
+		NewXORDataStore(),
+	}
+}
+
+func TestXORDataStoreAddRow(t *testing.T) {
+	for _, ds := range createXORDataStoreCases() {
+		testDataStoreAddRow(ds, t)
+	}
+}
+
+func TestXORDataStoreGetRow(t *testing.T) {
+	for _, ds := range createXORDataStoreCases() {
+		testDataStoreGetRow(ds, t)
+	}
+}
+
+func TestXORDataStoreGetNumRows(t *testing.T) {
+	for _, ds := range createXORDataStoreCases() {
+		testDataStoreGetNumRows(ds, t)
+	}
+}
+
+func TestXORDataStoreAddRowsGetRange(t *testing.T) {
+	// slowly changing coordinates with repetitions
+	values := make([]float64, 3*XORBlockSize+11)
+	for i := range values {
+		values[i] = 51.5 + float64(i/3)*0.25
+	}
+	values[XORBlockSize+1] = -1e300
+
+	for _, ds := range createXORDataStoreCases() {
+		testDataStoreAddRowsGetRange(ds, values, t)
+	}
+
+	ds := NewXORDataStore().(*XORDataStore)
+	ds.AddRows(FLOAT, values)
+	if ds.Bits.NumBits > len(values)*64/4 {
+		t.Errorf("%d values need %d bits", len(values), ds.Bits.NumBits)
+	}
+
+	for _, start := range []int{0, 5, XORBlockSize, XORBlockSize + 2, len(values) - 1} {
+		data, _ := ds.GetRange(start, len(values))
+		if !reflect.DeepEqual(data, values[start:]) {
+			t.Errorf("range starting at %d does not match", start)
+		}
+	}
+
+	col := NewColumnWithData(AttrInfo{"col", FLOAT, XOR, 0}, values)
+	if _, isXOR := col.Data.(*XORDataStore); !isXOR || !reflect.DeepEqual(col.GetRawData(), values) {
+		t.Error("XOR encoded column does not match")
+	}
+}
@@ -0,0 +1,231 @@
+package csgo
+
+import "math"
+
+// ZoneBlockSize is the number of rows summarized by a single zone of a ZoneMap.
+const ZoneBlockSize = 1024
+
+// Zone contains the metadata of a block of rows of a column.
+type Zone struct {
+	// Min and Max are the smallest and largest non-null values of the block (nil if there are none).
+	// NaN values are not included.
+	Min interface{}
+	Max interface{}
+	// NumRows is the number of rows of the block.
+	NumRows int
+	// NullCount is the number of NULL values of the block.
+	NullCount int
+	// NaNCount is the number of NaN values of the block.
+	NaNCount int
+}
+
+// ZoneMap keeps a Zone for every ZoneBlockSize consecutive rows of an ungrouped column, so
+// predicates can skip blocks which can't contain matching rows. The zones get updated as rows are
+// appended via Column.AddRow or Column.AddRows.
+type ZoneMap struct {
+	DataType DataTypes
+	Zones    []Zone
+	// NumRows is the number of rows covered by the zones. Rows added to the DataStore directly
+	// aren't covered and therefore never skipped.
+	NumRows int
+}
+
+// newZoneMap creates an empty ZoneMap for a column with the given signature. Grouped columns don't
+// get a zone map (nil).
+func newZoneMap(sig AttrInfo) *ZoneMap {
+	if sig.Flags&GROUPED != 0 {
+		return nil
+	}
+	return &ZoneMap{DataType: sig.Type, Zones: []Zone{}}
+}
+
+// add updates the zones with value, which has been added as row rowIndex.
+func (zm *ZoneMap) add(rowIndex int, value interface{}) {
+	if zm == nil || rowIndex != zm.NumRows {
+		return
+	}
+
+	switch typed := value.(type) {
+	case int:
+		addZoneValues(zm, []int{typed})
+	case float64:
+		addZoneValues(zm, []float64{typed})
+	case string:
+		addZoneValues(zm, []string{typed})
+	case nil:
+		zm.currentZone().NullCount++
+		zm.currentZone().NumRows++
+		zm.NumRows++
+	}
+}
+
+// addRows updates the zones with values (a slice of the column type), which have been added
+// starting at row firstIndex.
+func (zm *ZoneMap) addRows(firstIndex int, values interface{}) {
+	if zm == nil || firstIndex != zm.NumRows {
+		return
+	}
+
+	switch typed := values.(type) {
+	case []int:
+		addZoneValues(zm, typed)
+	case []float64:
+		addZoneValues(zm, typed)
+	case []string:
+		addZoneValues(zm, typed)
+	}
+}
+
+// currentZone returns the zone the next row belongs to (creating it if necessary).
+func (zm *ZoneMap) currentZone() *Zone {
+	if len(zm.Zones)*ZoneBlockSize == zm.NumRows {
+		zm.Zones = append(zm.Zones, Zone{})
+	}
+	return &zm.Zones[len(zm.Zones)-1]
+}
+
+// addZoneValues appends values to the zones of zm. The minimum and maximum are tracked unboxed and
+// only stored once per block. NaN values are only counted, since they can't be ordered.
+func addZoneValues[T ordered](zm *ZoneMap, values []T) {
+	for len(values) > 0 {
+		zone := zm.currentZone()
+
+		count := ZoneBlockSize - zone.NumRows
+		if count > len(values) {
+			count = len(values)
+		}
+
+		var low, high T
+		found := zone.Min != nil
+		if found {
+			low, high = zone.Min.(T), zone.Max.(T)
+		}
+		for _, value := range values[:count] {
+			switch {
+			case value != value:
+				zone.NaNCount++
+			case !found:
+				low, high, found = value, value, true
+			case value < low:
+				low = value
+			case value > high:
+				high = value
+			}
+		}
+
+		if found {
+			zone.Min, zone.Max = low, high
+		}
+		zone.NumRows += count
+		zm.NumRows += count
+		values = values[count:]
+	}
+}
+
+// mayMatch reports whether the block summarized by zone can contain a value satisfying the
+// predicate (value comp compVal). NULL values never match, NaN values only match NEQ. The constant
+// of an IN predicate has to be a set created by newValueSet.
+func (zone Zone) mayMatch(typ DataTypes, comp Comparison, compVal interface{}) bool {
+	if comp == NEQ && (zone.NaNCount > 0 || isNaN(compVal)) {
+		return zone.NumRows > zone.NullCount
+	}
+	if zone.Min == nil {
+		return false
+	}
+
+	switch set := compVal.(type) {
+	case map[int]struct{}:
+		return zoneContainsAny(zone, set)
+	case map[float64]struct{}:
+		return zoneContainsAny(zone, set)
+	case map[string]struct{}:
+		return zoneContainsAny(zone, set)
+	}
+
+	less := compFuncs[typ][LT]
+	switch comp {
+	case EQ:
+		return !less(compVal, zone.Min) && !less(zone.Max, compVal)
+	case NEQ:
+		return less(zone.Min, compVal) || less(compVal, zone.Max)
+	case LT:
+		return less(zone.Min, compVal)
+	case LEQ:
+		return !less(compVal, zone.Min)
+	case GT:
+		return less(compVal, zone.Max)
+	case GEQ:
+		return !less(zone.Max, compVal)
+	}
+	return true
+}
+
+// zoneContainsAny reports whether a value of set lies within the range of zone, which is the
+// condition for an IN predicate to match.
+func zoneContainsAny[T ordered](zone Zone, set map[T]struct{}) bool {
+	low, high := zone.Min.(T), zone.Max.(T)
+	for value := range set {
+		if !(value < low) && !(value > high) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNaN reports whether value is a NaN FLOAT value.
+func isNaN(value interface{}) bool {
+	float, isFloat := value.(float64)
+	return isFloat && math.IsNaN(float)
+}
+
+// candidateRanges returns the (merged) subranges of [start, end) which can contain rows satisfying
+// the predicate (value comp compVal). Without a zone map the whole range is returned.
+func (zm *ZoneMap) candidateRanges(start int, end int, comp Comparison, compVal interface{}) []rowRange {
+	ranges := []rowRange{}
+	appendRange := func(rangeStart int, rangeEnd int) {
+		if rangeStart >= rangeEnd {
+			return
+		}
+		if len(ranges) > 0 && ranges[len(ranges)-1].End == rangeStart {
+			ranges[len(ranges)-1].End = rangeEnd
+			return
+		}
+		ranges = append(ranges, rowRange{rangeStart, rangeEnd})
+	}
+
+	covered := start
+	// zones can only be used if compVal is comparable with the column values (or a set of such
+	// values, see newValueSet)
+	if zm != nil && (hasType(zm.DataType, compVal) || comp == IN) {
+		for zoneIndex := start / ZoneBlockSize; zoneIndex < len(zm.Zones) && zoneIndex*ZoneBlockSize < end; zoneIndex++ {
+			zone := zm.Zones[zoneIndex]
+			zoneStart := zoneIndex * ZoneBlockSize
+			zoneEnd := zoneStart + zone.NumRows
+
+			if zone.mayMatch(zm.DataType, comp, compVal) {
+				appendRange(max(zoneStart, start), min(zoneEnd, end))
+			}
+			covered = max(covered, min(zoneEnd, end))
+		}
+	}
+
+	// rows not covered by the zone map need to be checked
+	appendRange(covered, end)
+	return ranges
+}
+
+// memoryUsage returns the estimated number of bytes used by the zone map.
+func (zm *ZoneMap) memoryUsage() int {
+	if zm == nil {
+		return 0
+	}
+
+	size := wordBytes + sliceHeaderBytes + wordBytes + (cap(zm.Zones)-len(zm.Zones))*(2*interfaceBytes+3*wordBytes)
+	for _, zone := range zm.Zones {
+		size += 2*interfaceBytes + 3*wordBytes
+		if zone.Min != nil {
+			size += valueBytes(zone.Min) + valueBytes(zone.Max)
+		}
+	}
+	return size
+}
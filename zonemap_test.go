@@ -0,0 +1,169 @@
+package csgo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestZoneMapAddRows(t *testing.T) {
+	values := make([]int, 2*ZoneBlockSize+10)
+	for i := range values {
+		values[i] = i % (ZoneBlockSize + 5)
+	}
+
+	col := NewColumn(AttrInfo{"col", INT, NOCOMP, 0})
+	col.AddRows(INT, values[:100])
+	for _, value := range values[100 : ZoneBlockSize+3] {
+		col.AddRow(INT, value)
+	}
+	col.AddRows(INT, values[ZoneBlockSize+3:])
+
+	expected := &ZoneMap{DataType: INT, NumRows: len(values), Zones: []Zone{
+		{Min: 0, Max: ZoneBlockSize - 1, NumRows: ZoneBlockSize},
+		{Min: 0, Max: ZoneBlockSize + 4, NumRows: ZoneBlockSize},
+		{Min: ZoneBlockSize - 5, Max: ZoneBlockSize + 4, NumRows: 10},
+	}}
+	if !reflect.DeepEqual(col.Zones, expected) {
+		t.Errorf("unexpected zone map %v", col.Zones)
+	}
+
+	// rows added bypassing the column aren't covered
+	col.Data.(DataStore).AddRow(INT, 42)
+	col.AddRow(INT, 43)
+	if col.Zones.NumRows != len(values) {
+		t.Errorf("zone map covers %d rows", col.Zones.NumRows)
+	}
+
+	zm := newZoneMap(AttrInfo{"col", STRING, NOCOMP, 0})
+	zm.add(0, nil)
+	zm.add(1, "b")
+	zm.add(2, "a")
+	if !reflect.DeepEqual(zm.Zones, []Zone{{Min: "a", Max: "b", NumRows: 3, NullCount: 1}}) {
+		t.Errorf("unexpected zones %v", zm.Zones)
+	}
+
+	// NaN values are counted, but don't affect the minimum and maximum
+	zm = newZoneMap(AttrInfo{"col", FLOAT, NOCOMP, 0})
+	zm.addRows(0, []float64{math.NaN(), 2, 1})
+	if !reflect.DeepEqual(zm.Zones, []Zone{{Min: 1.0, Max: 2.0, NumRows: 3, NaNCount: 1}}) {
+		t.Errorf("unexpected zones %v", zm.Zones)
+	}
+
+	if newZoneMap(AttrInfo{"col", INT, NOCOMP, GROUPED}) != nil {
+		t.Error("grouped column got a zone map")
+	}
+}
+
+func TestZoneMapCandidateRanges(t *testing.T) {
+	// clustered values: block i contains the values [10*i, 10*i+9]
+	values := make([]int, 4*ZoneBlockSize)
+	for i := range values {
+		values[i] = 10*(i/ZoneBlockSize) + i%10
+	}
+	col := NewColumnWithData(AttrInfo{"col", INT, NOCOMP, 0}, values)
+	col.Data.(DataStore).AddRows(INT, []int{100, 200})
+
+	cases := []struct {
+		start   int
+		end     int
+		comp    Comparison
+		compVal interface{}
+		ranges  []rowRange
+	}{
+		{0, len(values), LT, 0, []rowRange{}},
+		{0, len(values), LT, 15, []rowRange{{0, 2 * ZoneBlockSize}}},
+		{0, len(values), EQ, 25, []rowRange{{2 * ZoneBlockSize, 3 * ZoneBlockSize}}},
+		{0, len(values), EQ, 10.5, []rowRange{{0, len(values)}}},
+		{0, len(values) + 2, GEQ, 39, []rowRange{{3 * ZoneBlockSize, len(values) + 2}}},
+		{0, len(values) + 2, GT, 39, []rowRange{{len(values), len(values) + 2}}},
+		{100, ZoneBlockSize + 100, NEQ, -1, []rowRange{{100, ZoneBlockSize + 100}}},
+		{100, ZoneBlockSize + 100, LEQ, 9, []rowRange{{100, ZoneBlockSize}}},
+	}
+
+	for testCaseID, testCase := range cases {
+		ranges := col.Zones.candidateRanges(testCase.start, testCase.end, testCase.comp, testCase.compVal)
+		if !reflect.DeepEqual(ranges, testCase.ranges) {
+			t.Errorf("test case %d: unexpected ranges %v", testCaseID, ranges)
+		}
+	}
+
+	// NaN values only match NEQ
+	nanCol := NewColumnWithData(AttrInfo{"col", FLOAT, NOCOMP, 0}, []float64{math.NaN(), math.NaN()})
+	for _, comp := range []Comparison{EQ, LT, GEQ} {
+		if ranges := nanCol.Zones.candidateRanges(0, 2, comp, 1.0); len(ranges) != 0 {
+			t.Errorf("NaN block can match %v: %v", comp, ranges)
+		}
+	}
+	if ranges := nanCol.Zones.candidateRanges(0, 2, NEQ, 1.0); !reflect.DeepEqual(ranges, []rowRange{{0, 2}}) {
+		t.Errorf("NaN block can't match NEQ: %v", ranges)
+	}
+	if ranges := col.Zones.candidateRanges(0, len(values), NEQ, math.NaN()); !reflect.DeepEqual(ranges, []rowRange{{0, len(values)}}) {
+		t.Errorf("unexpected ranges for NEQ NaN %v", ranges)
+	}
+
+	var noZones *ZoneMap
+	if ranges := noZones.candidateRanges(5, 10, EQ, 1); !reflect.DeepEqual(ranges, []rowRange{{5, 10}}) {
+		t.Errorf("unexpected ranges without zone map %v", ranges)
+	}
+}
+
+func TestRelationSelectZoneMap(t *testing.T) {
+	values := make([]float64, 3*ZoneBlockSize+7)
+	for i := range values {
+		values[i] = float64(i) / 100
+	}
+
+	for _, enc := range []Compression{NOCOMP, RLE, XOR} {
+		r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(AttrInfo{"col", FLOAT, enc, 0}, values)}}
+		// a view of all rows doesn't have a zone map, so every row gets scanned
+		scanned := Relation{Name: "rel", Columns: []Column{newColumnView(r.Columns[0].Signature, &r.Columns[0], rowIndices(len(values)))}}
+
+		for _, comp := range []Comparison{EQ, NEQ, LT, LEQ, GT, GEQ} {
+			for _, compVal := range []float64{-1, 0, 12.34, 20.47, 100} {
+				output, _ := r.ParallelSelect(AttrInfo{"col", FLOAT, enc, 0}, comp, compVal, 3).GetRawData()
+				expected, _ := scanned.ParallelSelect(AttrInfo{"col", FLOAT, enc, 0}, comp, compVal, 3).GetRawData()
+				if !reflect.DeepEqual(output, expected) {
+					t.Errorf("%v: selection (%v %v) does not match the full scan", enc, comp, compVal)
+				}
+			}
+		}
+	}
+}
+
+func TestRelationSelectZoneMapNaN(t *testing.T) {
+	values := make([]float64, ZoneBlockSize+10)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	values[0] = math.NaN()
+	r := Relation{Name: "rel", Columns: []Column{NewColumnWithData(AttrInfo{"col", FLOAT, NOCOMP, 0}, values)}}
+
+	cases := []struct {
+		comp     Comparison
+		compVal  float64
+		expected int
+	}{
+		{comp: GT, compVal: 1000, expected: ZoneBlockSize + 9 - 1000},
+		{comp: LT, compVal: 5, expected: 4},
+		{comp: NEQ, compVal: 5, expected: ZoneBlockSize + 9},
+		{comp: NEQ, compVal: math.NaN(), expected: ZoneBlockSize + 10},
+		{comp: EQ, compVal: math.NaN(), expected: 0},
+	}
+
+	for testCaseID, testCase := range cases {
+		output := r.Select(AttrInfo{"col", FLOAT, NOCOMP, 0}, testCase.comp, testCase.compVal).(Relation)
+		if numRows := output.Columns[0].GetNumRows(); numRows != testCase.expected {
+			t.Errorf("test case %d: expected %d rows, got %d", testCaseID, testCase.expected, numRows)
+		}
+	}
+}
+
+// rowIndices returns the row indices [0, numRows).
+func rowIndices(numRows int) []int {
+	indices := make([]int, numRows)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}